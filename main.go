@@ -6,33 +6,181 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
+	"os/user"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
 	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
 )
 
 type LinearTicket struct {
+	// ID, when set, is a client-generated issue id so a retried create
+	// can't make a duplicate
+	ID            string
+	Title         string
+	Description   string
+	Estimate      string
+	Labels        []string
+	TeamId        string
+	AssigneeId    string
+	StatusId      string
+	Priority      string
+	SubscriberIds []string
+	// Fields are extra issueCreate input fields from --field
+	Fields map[string]interface{}
+	// TemplateId is a Linear issue template applied server-side
+	TemplateId         string
+	ProjectId          string
+	ProjectMilestoneId string
+	// Position is "top" or "bottom" of the team's manual order, or empty
+	// for Linear's default placement
+	Position string
+	// Images are local files uploaded and appended to the description
+	Images []string
+	// ParentId is the UUID of the issue this one is a sub-issue of
+	ParentId string
+}
+
+type CreateOptions struct {
+	TeamName        string
+	JSONOutput      bool
+	AttachURL       string
+	AttachTitle     string
+	Template        string
+	TitleFromBranch bool
+	EstimateType    int
+	EstimateTypeSet bool
+	Estimate        string
+	// EstimateRaw is an --estimate-raw integer sent as is, skipping the
+	// team's estimate scale
+	EstimateRaw     string
+	SaveSnippet     bool
+	IncludeInactive bool
+	Open            bool
+	CopyBranch      bool
+	// NoPostMenu skips the menu shown after creating; --quiet implies it
+	NoPostMenu bool
+	// Slack copies a Slack-formatted link to the created issue
+	Slack          bool
+	Blocks         []string
+	BlockedBy      []string
+	StatusType     string
+	TruncateTitle  bool
+	IdempotencyKey string
+	Unassigned     bool
+	// CreateMissingLabels creates Labels the team doesn't have yet instead
+	// of skipping them
+	CreateMissingLabels bool
+	// ParentAuto nests the issue under the one named in the current git
+	// branch; ParentId is that issue's UUID once resolved
+	ParentAuto bool
+	ParentId   string
+	// NoSubscribers skips the team's configured default subscribers
+	NoSubscribers bool
+	Fields        map[string]interface{}
+	OutputFile    string
+	OutputFormat  string
+	TemplateID    string
+	Position      string
+	Images        []string
+	TeamAll       bool
+	// Minimal shows only the title and description in the form
+	Minimal bool
+	// Preview asks for confirmation before creating, with an option to
+	// render the description
+	Preview bool
+	// DescriptionTemplate is the rendered --description-template scaffold
+	DescriptionTemplate string
+	// Fields from --description-file (Assignee also from --assignee)
 	Title       string
 	Description string
-	Estimate    string
 	Labels      []string
-	TeamId      string
-	AssigneeId  string
-	StatusId    string
+	Assignee    string
+}
+
+// ImportTicket is one row of an --import file. Labels, assignee, and team
+// are given by name and resolved per team.
+type ImportTicket struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Labels      []string       `json:"labels"`
+	Assignee    string         `json:"assignee"`
+	Estimate    importEstimate `json:"estimate"`
+	Team        string         `json:"team"`
+}
+
+// importEstimate accepts either a JSON number or a string such as "M".
+type importEstimate string
+
+func (e *importEstimate) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*e = importEstimate(value)
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("estimate must be a number or string: %s", data)
+	}
+	*e = importEstimate(number.String())
+
+	return nil
+}
+
+type SearchOptions struct {
+	Query      string
+	TeamName   string
+	Limit      int
+	JSONOutput bool
+}
+
+type IssueRef struct {
+	ID     string
+	TeamID string
+}
+
+// QueuedTicket is a ticket filed with --offline, waiting to be created.
+type QueuedTicket struct {
+	Ticket      LinearTicket      `json:"ticket"`
+	LabelIDs    map[string]string `json:"labelIds"`
+	AttachURL   string            `json:"attachUrl,omitempty"`
+	AttachTitle string            `json:"attachTitle,omitempty"`
+	Blocks      []string          `json:"blocks,omitempty"`
+	BlockedBy   []string          `json:"blockedBy,omitempty"`
+	QueuedAt    time.Time         `json:"queuedAt"`
 }
 
 type CreatedIssue struct {
@@ -50,32 +198,228 @@ type Issue struct {
 }
 
 type UserSelections struct {
-	TeamId     string   `json:"teamId"`
-	AssigneeId string   `json:"assigneeId"`
-	Labels     []string `json:"labels"`
-	Estimate   string   `json:"estimate"`
-	StatusId   string   `json:"statusId"`
+	TeamId              string            `json:"teamId"`
+	AssigneeId          string            `json:"assigneeId"`
+	Labels              []string          `json:"labels"`
+	Estimate            string            `json:"estimate"`
+	StatusId            string            `json:"statusId"`
+	SubscriberIds       []string          `json:"subscriberIds"`
+	DescriptionSnippets map[string]string `json:"descriptionSnippets,omitempty"`
+	// Teams holds the last choices for teams other than TeamId, so switching
+	// back to a team restores its own labels, assignee, and status.
+	Teams map[string]TeamSelections `json:"teams,omitempty"`
+	// TeamUsage (by team id) and LabelUsage (by label name) order the
+	// pickers so the teams and labels used most lately come first.
+	TeamUsage  map[string]Usage `json:"teamUsage,omitempty"`
+	LabelUsage map[string]Usage `json:"labelUsage,omitempty"`
+	// RepoTeams maps a git repo (see repoKey) to the team last filed into
+	// from it.
+	RepoTeams map[string]string `json:"repoTeams,omitempty"`
+}
+
+type Usage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+type TeamSelections struct {
+	AssigneeId    string   `json:"assigneeId"`
+	Labels        []string `json:"labels"`
+	Estimate      string   `json:"estimate"`
+	StatusId      string   `json:"statusId"`
+	SubscriberIds []string `json:"subscriberIds"`
+}
+
+// forTeam returns the saved choices for teamId.
+func (s UserSelections) forTeam(teamId string) TeamSelections {
+	if teamId == s.TeamId {
+		return TeamSelections{
+			AssigneeId:    s.AssigneeId,
+			Labels:        s.Labels,
+			Estimate:      s.Estimate,
+			StatusId:      s.StatusId,
+			SubscriberIds: s.SubscriberIds,
+		}
+	}
+
+	return s.Teams[teamId]
+}
+
+// switchTeam makes teamId the current team, stashing the current team's
+// choices and restoring teamId's.
+func (s *UserSelections) switchTeam(teamId string) {
+	if teamId == s.TeamId {
+		return
+	}
+
+	if s.TeamId != "" {
+		if s.Teams == nil {
+			s.Teams = make(map[string]TeamSelections)
+		}
+		s.Teams[s.TeamId] = s.forTeam(s.TeamId)
+	}
+
+	team := s.Teams[teamId]
+	delete(s.Teams, teamId)
+	s.TeamId = teamId
+	s.AssigneeId = team.AssigneeId
+	s.Labels = team.Labels
+	s.Estimate = team.Estimate
+	s.StatusId = team.StatusId
+	s.SubscriberIds = team.SubscriberIds
+}
+
+// Project is an active project of a team; ProjectMilestone is one of its
+// milestones.
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ProjectMilestone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// LinearTemplate is an issue template defined for a team in Linear.
+type LinearTemplate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TicketTemplate struct {
+	TitlePrefix string   `json:"titlePrefix"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+	Estimate    string   `json:"estimate"`
+	Priority    string   `json:"priority"`
+}
+
+type TemplateData struct {
+	Date   string
+	Branch string
+}
+
+type Config struct {
+	DisableHistory  bool `json:"disableHistory"`
+	OpenAfterCreate bool `json:"openAfterCreate"`
+	// DefaultAssignees maps a team (key, name, or id) to the assignee (email,
+	// name, or id) used when nothing else picks one.
+	DefaultAssignees map[string]string `json:"defaultAssignees,omitempty"`
+	// DefaultSubscribers maps a team to the subscribers (emails, names, or
+	// ids) added when none were picked. Teams not listed get none.
+	DefaultSubscribers map[string][]string `json:"defaultSubscribers,omitempty"`
+	// WarmCache prefetches the other teams' labels, users, and states in the
+	// background once a team is picked, at the cost of more API calls.
+	WarmCache bool `json:"warmCache,omitempty"`
+	// AssigneeTeamOnly makes --assignee-team-only the default.
+	AssigneeTeamOnly bool `json:"assigneeTeamOnly,omitempty"`
+	// RepoTeams maps a git remote (e.g. github.com/org/repo) to the team
+	// (key, name, or id) tickets filed from that repo default to.
+	RepoTeams map[string]string `json:"repoTeams,omitempty"`
+	// BranchTemplate renders branch names instead of Linear's, e.g.
+	// "{{.User}}/{{.Identifier | lower}}-{{.Title | slug}}".
+	BranchTemplate string `json:"branchTemplate,omitempty"`
+	// APIKeys are extra API keys for the same workspace. When set, bulk
+	// commands rotate through them and LINEAR_API_KEY to spread rate limits.
+	APIKeys []string `json:"apiKeys,omitempty"`
+	// HoursPerPoint shows each estimate's hour equivalent (e.g. 4 for
+	// 1pt=4h) in the estimate picker and summary. Zero leaves it off.
+	HoursPerPoint float64 `json:"hoursPerPoint,omitempty"`
+}
+
+// defaultAssignee returns the configured default assignee for team.
+func (c Config) defaultAssignee(team Team) string {
+	for key, assignee := range c.DefaultAssignees {
+		if key == team.ID || strings.EqualFold(key, team.Key) || strings.EqualFold(key, team.Name) {
+			return assignee
+		}
+	}
+
+	return ""
+}
+
+// defaultSubscribers returns the configured default subscribers for team.
+func (c Config) defaultSubscribers(team Team) []string {
+	for key, subscribers := range c.DefaultSubscribers {
+		if key == team.ID || strings.EqualFold(key, team.Key) || strings.EqualFold(key, team.Name) {
+			return subscribers
+		}
+	}
+
+	return nil
+}
+
+type HistoryEntry struct {
+	Identifier string    `json:"identifier"`
+	Title      string    `json:"title"`
+	TeamId     string    `json:"teamId"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// AuditEntry is one line of the LNR_LOG_FILE audit log.
+type AuditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Identifier string    `json:"identifier"`
+	Team       string    `json:"team"`
+	TeamId     string    `json:"teamId"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	User       string    `json:"user"`
 }
 
+// cacheSchemaVersion is bumped whenever the shape of cached data changes, so
+// entries written by another version are refetched instead of misread.
+const cacheSchemaVersion = 1
+
 type CacheEntry struct {
+	Version   int         `json:"version"`
 	Data      interface{} `json:"data"`
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+type CacheInfo struct {
+	Key       string    `json:"key"`
+	TeamName  string    `json:"teamName,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Status    string    `json:"status"`
+}
+
 type Label struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	GroupID   string `json:"groupId,omitempty"`
+	GroupName string `json:"groupName,omitempty"`
+	Color     string `json:"color,omitempty"`
+	IsGroup   bool   `json:"isGroup,omitempty"`
 }
 
 type Team struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
+	Key  string `json:"key"`
 }
 
 type User struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Inactive bool   `json:"inactive,omitempty"`
+}
+
+type TeamSettings struct {
+	ID                  string  `json:"id"`
+	Name                string  `json:"name"`
+	Key                 string  `json:"key"`
+	EstimationType      string  `json:"estimationType"`
+	EstimationAllowZero bool    `json:"estimationAllowZero"`
+	DefaultEstimate     float64 `json:"defaultEstimate"`
+	TriageEnabled       bool    `json:"triageEnabled"`
+	CyclesEnabled       bool    `json:"cyclesEnabled"`
+	CycleDuration       int     `json:"cycleDuration"`
+	CycleStartDay       int     `json:"cycleStartDay"`
+	DefaultStateName    string  `json:"defaultStateName"`
 }
 
 type WorkflowState struct {
@@ -85,13 +429,141 @@ type WorkflowState struct {
 }
 
 const noCacheExpiration time.Duration = 0
+const teamSettingsCacheTTL = 24 * time.Hour
+const viewerCacheTTL = 7 * 24 * time.Hour
 const userSelectionsCacheKey = "user-selections"
 const userSelectionsConfigFile = "defaults.json"
+const ticketTemplatesConfigFile = "templates.json"
+const descriptionTemplatesDir = "descriptions"
+const configFile = "config.json"
+const historyFile = "history.jsonl"
+const maxHistoryFileSize = 1 << 20
+const queueFile = "queue.json"
+const queueLockFile = "queue.lock"
+
+// cacheLockStale is how old a cache file lock must be before it's taken to be
+// left over from a crashed run.
+const cacheLockStale = 30 * time.Second
+const idempotencyKeysFile = "idempotency-keys.json"
+const idempotencyKeysLockFile = "idempotency-keys.lock"
+
+// idempotencyKeyTTL is how long an --idempotency-key keeps its issue id.
+const idempotencyKeyTTL = 30 * 24 * time.Hour
+
+// exitCancelled is the exit status when the user aborts, matching SIGINT.
+const exitCancelled = 130
+
+// maxTitleLength is the longest issue title Linear accepts, in characters.
+const maxTitleLength = 255
+
+// autoEstimate as the --estimate value picks the team's default estimate.
+const autoEstimate = "auto"
 const mcpAuthHeaderPrefix = "mcp:"
 const oauthTokenCacheKey = "oauth-token"
 const oauthTokenRefreshSkew = time.Minute
 const defaultOAuthScopes = "read write"
 
+// Set at build time via -ldflags "-X main.version=... -X main.commit=... -X main.date=...".
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// verbosity is 1 with --verbose and 2 with -vv.
+var verbosity int
+
+// quietOutput suppresses decorative output; set with --quiet.
+var quietOutput bool
+
+// offlineMode serves everything from the cache and queues created tickets;
+// set with --offline.
+var offlineMode bool
+
+// teamMembersOnly limits assignee and subscriber pickers to the team's
+// members instead of the whole organization; set with --assignee-team-only
+// or "assigneeTeamOnly" in config.json.
+var teamMembersOnly bool
+
+// strictLabels fails a create instead of dropping labels the team doesn't
+// have; set with --strict.
+var strictLabels bool
+
+var errOffline = errors.New("not available offline (run lnr once while online to cache it)")
+
+// errUnauthorized marks a request Linear rejected with 401, usually an expired or revoked token.
+var errUnauthorized = errors.New("Linear rejected the credentials (401 Unauthorized)")
+
+// plainOutput swaps emoji and box drawing for ASCII; set with --plain.
+var plainOutput bool
+
+// Output glyphs, written as escapes so they survive tools that mangle non-ASCII source.
+const (
+	glyphError    = "\u274c"        // ❌
+	glyphSuccess  = "\u2705"        // ✅
+	glyphInfo     = "\u2139\ufe0f " // ℹ️
+	glyphCreate   = "\U0001f680"    // 🚀
+	glyphCopy     = "\U0001f4cb"    // 📋
+	glyphLink     = "\U0001f517"    // 🔗
+	glyphTicket   = "\U0001f4dd"    // 📝
+	glyphRequest  = "\u2192"        // →
+	glyphResponse = "\u2190"        // ←
+	glyphRule     = "\u2501"        // ━
+	glyphSwatch   = "\u25cf"        // ●
+	glyphBar      = "\u25ae"        // ▮
+	glyphNoBar    = "\u25af"        // ▯
+	glyphUrgent   = "\u203c"        // ‼
+	glyphEllipsis = "\u2026"        // …
+)
+
+// Output symbols, replaced by usePlainOutput.
+var (
+	errorSymbol    = glyphError
+	successSymbol  = glyphSuccess
+	infoSymbol     = glyphInfo
+	createSymbol   = glyphCreate
+	copySymbol     = glyphCopy
+	linkSymbol     = glyphLink
+	ticketSymbol   = glyphTicket
+	requestSymbol  = glyphRequest
+	responseSymbol = glyphResponse
+	ruleLine       = strings.Repeat(glyphRule, 40)
+	ellipsisSymbol = glyphEllipsis
+)
+
+// utf8Locale reports whether the locale environment allows UTF-8 output.
+// An unset locale is assumed to be UTF-8.
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+
+	return true
+}
+
+// usePlainOutput switches to ASCII symbols and drops colors, for terminals
+// and log collectors that mangle Unicode. It's used for --plain and when the
+// locale isn't UTF-8.
+func usePlainOutput() {
+	plainOutput = true
+	errorSymbol = "[error]"
+	successSymbol = "[ok]"
+	infoSymbol = "[info]"
+	createSymbol = ">>"
+	copySymbol = "[copied]"
+	linkSymbol = "[linked]"
+	ticketSymbol = "#"
+	requestSymbol = "->"
+	responseSymbol = "<-"
+	ruleLine = strings.Repeat("-", 40)
+	ellipsisSymbol = "..."
+	spinnerFrames = []string{"|", "/", "-", "\\"}
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
 var linearOAuthAuthorizeURL = "https://mcp.linear.app/authorize"
 var linearOAuthRegistrationURL = "https://mcp.linear.app/register"
 var linearOAuthResource = "https://mcp.linear.app/mcp"
@@ -152,8 +624,10 @@ type MCPIssue struct {
 	GitBranchName string `json:"gitBranchName"`
 }
 
+// getCacheDir and getConfigDir follow the XDG base directory spec, which
+// says relative XDG_* paths are invalid and must be ignored.
 func getCacheDir() string {
-	if xdgCacheHome := os.Getenv("XDG_CACHE_HOME"); xdgCacheHome != "" {
+	if xdgCacheHome := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(xdgCacheHome) {
 		return filepath.Join(xdgCacheHome, "lnr")
 	}
 
@@ -162,7 +636,7 @@ func getCacheDir() string {
 }
 
 func getConfigDir() string {
-	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdgConfigHome) {
 		return filepath.Join(xdgConfigHome, "lnr")
 	}
 
@@ -193,8 +667,12 @@ func loadFromCache(key string, ttl time.Duration) (interface{}, bool) {
 	if err := json.Unmarshal(data, &entry); err != nil {
 		return nil, false
 	}
+	if entry.Version != cacheSchemaVersion {
+		return nil, false
+	}
 
-	if ttl > 0 && time.Since(entry.Timestamp) > ttl {
+	// Stale data beats no data when offline
+	if ttl > 0 && !offlineMode && time.Since(entry.Timestamp) > ttl {
 		return nil, false
 	}
 
@@ -223,6 +701,7 @@ func loadTypedFromCache[T any](key string, ttl time.Duration) (T, bool) {
 func saveToCache(key string, data interface{}) error {
 	cachePath := getCachePath(key)
 	entry := CacheEntry{
+		Version:   cacheSchemaVersion,
 		Data:      data,
 		Timestamp: time.Now(),
 	}
@@ -232,2152 +711,7695 @@ func saveToCache(key string, data interface{}) error {
 		return err
 	}
 
-	return os.WriteFile(cachePath, jsonData, 0644)
+	return writeFileAtomic(cachePath, jsonData, 0644)
 }
 
-func clearCache() error {
-	cacheDir := getCacheDir()
-	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
-		return nil // Cache directory doesn't exist, nothing to clear
+// writeFileAtomic writes to a temp file in the same directory and renames it
+// into place, so an interrupted write never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
 	}
-	return os.RemoveAll(cacheDir)
-}
+	tmpPath := tmpFile.Name()
 
-func clearConfig() error {
-	configDir := getConfigDir()
-	if _, err := os.Stat(configDir); os.IsNotExist(err) {
-		return nil
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
 	}
-	return os.RemoveAll(configDir)
-}
 
-func resetData() error {
-	if err := clearCache(); err != nil {
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
 		return err
 	}
 
-	return clearConfig()
+	return nil
 }
 
-func getLinearAuthHeader() string {
-	apiKey := os.Getenv("LINEAR_API_KEY")
-	if apiKey != "" {
-		return apiKey
+// clearCache removes cached API data but keeps the offline queue, which holds
+// tickets that haven't been created yet.
+func clearCache() error {
+	paths, err := cacheFilesToClear()
+	if err != nil {
+		return err
 	}
 
-	accessToken := os.Getenv("LINEAR_OAUTH_ACCESS_TOKEN")
-	if accessToken != "" {
-		return bearerAuthHeader(accessToken)
+	for _, path := range paths {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
 	}
 
-	scopes := oauthScopes()
-
-	if cache, found := loadOAuthTokenCache(scopes); found {
-		if cache.ExpiresAt.After(time.Now().Add(oauthTokenRefreshSkew)) {
-			return mcpAuthHeader(cache.AccessToken)
-		}
+	return nil
+}
 
-		if cache.RefreshToken != "" && cache.ClientID != "" {
-			token, err := refreshOAuthAccessToken(cache.ClientID, cache.RefreshToken, scopes)
-			if err == nil {
-				if err := saveOAuthToken(cache.ClientID, scopes, token, cache.RefreshToken); err == nil {
-					return mcpAuthHeader(token.AccessToken)
-				}
-			}
-			fmt.Println("Cached Linear OAuth token expired; starting a new browser login.")
-		}
+// cacheFilesToClear lists what clearCache removes: everything in the cache
+// directory except the offline queue and idempotency keys.
+func cacheFilesToClear() ([]string, error) {
+	cacheDir := getCacheDir()
+	entries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil // Cache directory doesn't exist, nothing to clear
 	}
-
-	token, err := runDCRLogin(scopes)
 	if err != nil {
-		fmt.Printf("❌ Error signing in to Linear: %v\n", err)
-		fmt.Println("\nYou can still use a personal API key instead:")
-		fmt.Println("  export LINEAR_API_KEY='your-api-key'")
-		os.Exit(1)
+		return nil, err
 	}
 
-	return mcpAuthHeader(token.AccessToken)
-}
-
-func oauthScopes() string {
-	scopes := os.Getenv("LINEAR_OAUTH_SCOPES")
-	if scopes == "" {
-		return defaultOAuthScopes
+	var paths []string
+	for _, entry := range entries {
+		switch entry.Name() {
+		case queueFile, queueLockFile, idempotencyKeysFile, idempotencyKeysLockFile:
+			continue
+		}
+		paths = append(paths, filepath.Join(cacheDir, entry.Name()))
 	}
 
-	return scopes
+	return paths, nil
 }
 
-func bearerAuthHeader(token string) string {
-	if strings.HasPrefix(strings.ToLower(token), "bearer ") {
-		return token
+// cacheTTL mirrors the TTL each loader passes to loadTypedFromCache.
+func cacheTTL(key string) time.Duration {
+	for _, prefix := range []string{"settings-", "templates-", "projects-", "milestones-"} {
+		if strings.HasPrefix(key, prefix) {
+			return teamSettingsCacheTTL
+		}
 	}
-
-	return "Bearer " + token
-}
-
-func mcpAuthHeader(token string) string {
-	return mcpAuthHeaderPrefix + bearerAuthHeader(token)
-}
-
-func splitMCPAuthHeader(authHeader string) (string, bool) {
-	if !strings.HasPrefix(authHeader, mcpAuthHeaderPrefix) {
-		return authHeader, false
+	if strings.HasPrefix(key, "viewer-") {
+		return viewerCacheTTL
 	}
 
-	return strings.TrimPrefix(authHeader, mcpAuthHeaderPrefix), true
+	return noCacheExpiration
 }
 
-func loadOAuthTokenCache(scopes string) (OAuthTokenCache, bool) {
-	data, err := os.ReadFile(getCachePath(oauthTokenCacheKey))
+// listCache describes every file in the cache dir without touching the network.
+func listCache() ([]CacheInfo, error) {
+	entries, err := os.ReadDir(getCacheDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
 	if err != nil {
-		return OAuthTokenCache{}, false
+		return nil, err
 	}
 
-	var cache OAuthTokenCache
-	if err := json.Unmarshal(data, &cache); err != nil {
-		return OAuthTokenCache{}, false
+	teamNames := make(map[string]string)
+	if teams, found := loadTypedFromCache[[]Team]("teams", noCacheExpiration); found {
+		for _, team := range teams {
+			teamNames[team.ID] = team.Name
+		}
 	}
 
-	if cache.Scope != scopes || cache.AccessToken == "" {
-		return OAuthTokenCache{}, false
+	var infos []CacheInfo
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		key := strings.TrimSuffix(entry.Name(), ".json")
+		info := CacheInfo{Key: key}
+		if _, teamId, ok := strings.Cut(key, "-"); ok {
+			info.TeamName = teamNames[teamId]
+		}
+
+		switch entry.Name() {
+		case queueFile:
+			info.Status = "offline queue"
+		case idempotencyKeysFile:
+			info.Status = "idempotency keys"
+		case oauthTokenCacheKey + ".json":
+			info.Status = "OAuth token"
+		default:
+			data, err := os.ReadFile(filepath.Join(getCacheDir(), entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			var cacheEntry CacheEntry
+			if err := json.Unmarshal(data, &cacheEntry); err != nil {
+				info.Status = "unreadable"
+				break
+			}
+			info.UpdatedAt = cacheEntry.Timestamp
+			ttl := cacheTTL(key)
+			switch {
+			case cacheEntry.Version != cacheSchemaVersion:
+				info.Status = "outdated"
+			case ttl == noCacheExpiration:
+				info.Status = "never expires"
+			case time.Since(cacheEntry.Timestamp) > ttl:
+				info.Status = "expired"
+			default:
+				info.Status = "expires in " + formatAge(ttl-time.Since(cacheEntry.Timestamp))
+			}
+		}
+		if info.UpdatedAt.IsZero() {
+			if fileInfo, err := entry.Info(); err == nil {
+				info.UpdatedAt = fileInfo.ModTime()
+			}
+		}
+		infos = append(infos, info)
 	}
 
-	return cache, true
+	return infos, nil
 }
 
-func saveOAuthToken(clientID, scopes string, token OAuthTokenResponse, fallbackRefreshToken string) error {
-	refreshToken := token.RefreshToken
-	if refreshToken == "" {
-		refreshToken = fallbackRefreshToken
+// cacheKinds are the per-team cache key prefixes, plus "teams".
+var cacheKinds = []string{"teams", "labels", "users", "members", "states", "settings", "templates", "projects", "milestones"}
+
+// cacheKeysToClear picks keys for "lnr cache clear <kind|team|key> [team]":
+// a kind (optionally for one team), every entry for a team, or an exact key.
+// Milestones are cached per project, so they're cleared only as a kind. The
+// offline queue and idempotency keys are never included.
+func cacheKeysToClear(keys []string, teams []Team, args []string) ([]string, error) {
+	var team *Team
+	if len(args) > 1 {
+		if args[0] == "milestones" {
+			return nil, fmt.Errorf("milestones are cached per project, not per team; run lnr cache clear milestones")
+		}
+		if team = matchTeam(teams, args[1]); team == nil {
+			return nil, fmt.Errorf("team not found in cache: %s", args[1])
+		}
 	}
 
-	expiresIn := token.ExpiresIn
-	if expiresIn <= 0 {
-		expiresIn = int64((24 * time.Hour).Seconds())
+	target := args[0]
+	var matches []string
+	switch {
+	case hasString(cacheKinds, target):
+		for _, key := range keys {
+			if key == target || (strings.HasPrefix(key, target+"-") && (team == nil || key == target+"-"+team.ID)) {
+				matches = append(matches, key)
+			}
+		}
+	case team == nil && matchTeam(teams, target) != nil:
+		team = matchTeam(teams, target)
+		for _, key := range keys {
+			if strings.HasSuffix(key, "-"+team.ID) {
+				matches = append(matches, key)
+			}
+		}
+	case hasString(keys, target):
+		matches = []string{target}
+	default:
+		return nil, fmt.Errorf("nothing cached for %s (see lnr cache list)", target)
 	}
 
-	return saveOAuthTokenCache(OAuthTokenCache{
-		AccessToken:  token.AccessToken,
-		TokenType:    token.TokenType,
-		RefreshToken: refreshToken,
-		Scope:        scopes,
-		ClientID:     clientID,
-		ExpiresAt:    time.Now().Add(time.Duration(expiresIn) * time.Second),
-	})
+	queueKey := strings.TrimSuffix(queueFile, ".json")
+	idempotencyKey := strings.TrimSuffix(idempotencyKeysFile, ".json")
+	cleared := matches[:0]
+	for _, key := range matches {
+		if key != queueKey && key != idempotencyKey {
+			cleared = append(cleared, key)
+		}
+	}
+
+	return cleared, nil
 }
 
-func saveOAuthTokenCache(cache OAuthTokenCache) error {
-	jsonData, err := json.MarshalIndent(cache, "", "  ")
-	if err != nil {
-		return err
+func hasString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
 
-	cachePath := getCachePath(oauthTokenCacheKey)
-	if err := os.WriteFile(cachePath, jsonData, 0600); err != nil {
-		return err
-	}
-	return os.Chmod(cachePath, 0600)
+	return false
 }
 
-func clearOAuthTokenCache() error {
-	err := os.Remove(getCachePath(oauthTokenCacheKey))
-	if os.IsNotExist(err) {
-		return nil
+// formatAge renders a duration in its largest whole unit, e.g. "3d" or "12m".
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	default:
+		return fmt.Sprintf("%ds", int(d/time.Second))
 	}
-	return err
 }
 
-type oauthCallbackResult struct {
-	code string
-	err  error
+// timeAgo describes t relative to now, e.g. "2h ago". Anything older than a
+// month is shown as its date instead, and times in the future as "just now".
+func timeAgo(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Second:
+		return "just now"
+	case d >= 30*24*time.Hour:
+		return t.Local().Format("2006-01-02")
+	default:
+		return formatAge(d) + " ago"
+	}
 }
 
-func runDCRLogin(scopes string) (OAuthTokenResponse, error) {
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		return OAuthTokenResponse{}, err
+func runCache(args []string, jsonOutput, dryRun bool) {
+	if len(args) == 0 || hasHelpArg(args) {
+		printCacheUsage()
+		return
 	}
 
-	callbackURL := fmt.Sprintf("http://%s/oauth/callback", listener.Addr().String())
-	client, err := registerOAuthClient(callbackURL, scopes)
-	if err != nil {
-		listener.Close()
-		return OAuthTokenResponse{}, err
-	}
+	switch args[0] {
+	case "path":
+		fmt.Println(getCacheDir())
+	case "list":
+		infos, err := listCache()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error reading cache: %v\n", err)
+			os.Exit(1)
+		}
 
-	state, err := randomURLSafeString(32)
-	if err != nil {
-		listener.Close()
-		return OAuthTokenResponse{}, err
-	}
-	codeVerifier, err := randomURLSafeString(64)
-	if err != nil {
-		listener.Close()
-		return OAuthTokenResponse{}, err
-	}
+		if jsonOutput || hasJSONArg(args[1:]) {
+			if infos == nil {
+				infos = []CacheInfo{}
+			}
+			jsonData, err := json.Marshal(infos)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+				os.Exit(1)
+			}
 
-	resultCh := make(chan oauthCallbackResult, 1)
-	server := &http.Server{Handler: oauthCallbackHandler(state, resultCh)}
-	go func() {
-		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
-			select {
-			case resultCh <- oauthCallbackResult{err: err}:
-			default:
+			fmt.Println(string(jsonData))
+			return
+		}
+
+		if len(infos) == 0 {
+			fmt.Println("Cache is empty")
+			return
+		}
+		for _, info := range infos {
+			key := info.Key
+			if info.TeamName != "" {
+				key += " (" + info.TeamName + ")"
 			}
+			fmt.Printf("%-60s %6s  %s\n", key, formatAge(time.Since(info.UpdatedAt)), info.Status)
 		}
-	}()
+	case "clear":
+		if slices.Contains(args, "--dry-run") {
+			dryRun = true
+			args = slices.DeleteFunc(slices.Clone(args), func(arg string) bool { return arg == "--dry-run" })
+		}
+		if len(args) < 2 {
+			printCacheUsage()
+			os.Exit(1)
+		}
+		infos, err := listCache()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error reading cache: %v\n", err)
+			os.Exit(1)
+		}
+		keys := make([]string, len(infos))
+		for i, info := range infos {
+			keys[i] = info.Key
+		}
+		teams, _ := loadTypedFromCache[[]Team]("teams", noCacheExpiration)
 
-	authURL, err := buildAuthorizationURL(client.ClientID, callbackURL, scopes, state, codeVerifier)
-	if err != nil {
-		shutdownOAuthServer(server)
-		return OAuthTokenResponse{}, err
+		cleared, err := cacheKeysToClear(keys, teams, args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		if dryRun {
+			fmt.Printf("Would clear %d cache entries:\n", len(cleared))
+			for _, key := range cleared {
+				fmt.Println("  " + getCachePath(key))
+			}
+			return
+		}
+		for _, key := range cleared {
+			if err := os.Remove(getCachePath(key)); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, errorSymbol+" Error removing %s: %v\n", key, err)
+				os.Exit(1)
+			}
+		}
+		if !quietOutput {
+			fmt.Printf(successSymbol+" Cleared %d cache entries: %s\n", len(cleared), strings.Join(cleared, ", "))
+		}
+	default:
+		printCacheUsage()
+		os.Exit(1)
 	}
+}
 
-	fmt.Println("Opening Linear sign-in in your browser...")
-	fmt.Println(authURL)
-	if err := openURL(authURL); err != nil {
-		fmt.Printf("Could not open browser automatically: %v\n", err)
-		fmt.Println("Open the URL above to continue.")
+// loadQueue reads tickets queued while offline.
+func loadQueue() ([]QueuedTicket, error) {
+	data, err := os.ReadFile(filepath.Join(getCacheDir(), queueFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	var result oauthCallbackResult
-	select {
-	case result = <-resultCh:
-	case <-time.After(5 * time.Minute):
-		shutdownOAuthServer(server)
-		return OAuthTokenResponse{}, fmt.Errorf("timed out waiting for OAuth callback")
+	var queue []QueuedTicket
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", queueFile, err)
 	}
-	shutdownOAuthServer(server)
 
-	if result.err != nil {
-		return OAuthTokenResponse{}, result.err
+	return queue, nil
+}
+
+func saveQueue(queue []QueuedTicket) error {
+	cacheDir := getCacheDir()
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
 	}
 
-	token, err := exchangeOAuthCode(client.ClientID, result.code, callbackURL, codeVerifier, scopes)
+	jsonData, err := json.MarshalIndent(queue, "", "  ")
 	if err != nil {
-		return OAuthTokenResponse{}, err
+		return err
 	}
 
-	if err := saveOAuthToken(client.ClientID, scopes, token, ""); err != nil {
-		return OAuthTokenResponse{}, err
-	}
+	return writeFileAtomic(filepath.Join(cacheDir, queueFile), jsonData, 0600)
+}
 
-	return token, nil
+// lockQueue keeps concurrent lnr runs from overwriting each other's queue
+// changes. It returns the func that releases the lock.
+func lockQueue() (func(), error) {
+	return lockCacheFile(queueLockFile, queueFile)
 }
 
-func oauthCallbackHandler(expectedState string, resultCh chan<- oauthCallbackResult) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/oauth/callback" {
-			http.NotFound(w, r)
-			return
-		}
+// lockCacheFile takes lockName in the cache directory with O_EXCL, waiting up
+// to 5 seconds for another run to release it, so target's read-modify-write
+// isn't interleaved with theirs. It returns the func that releases the lock.
+func lockCacheFile(lockName, target string) (func(), error) {
+	cacheDir := getCacheDir()
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, err
+	}
 
-		query := r.URL.Query()
-		if errorCode := query.Get("error"); errorCode != "" {
-			description := query.Get("error_description")
-			if description == "" {
-				description = errorCode
-			}
-			sendOAuthCallbackResult(resultCh, oauthCallbackResult{err: fmt.Errorf("OAuth authorization failed: %s", description)})
-			http.Error(w, "Linear authorization failed. You can close this tab.", http.StatusBadRequest)
-			return
+	path := filepath.Join(cacheDir, lockName)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > cacheLockStale {
+			os.Remove(path)
+			continue
 		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another lnr run", target)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
 
-		if query.Get("state") != expectedState {
-			sendOAuthCallbackResult(resultCh, oauthCallbackResult{err: fmt.Errorf("OAuth state mismatch")})
-			http.Error(w, "OAuth state mismatch. You can close this tab.", http.StatusBadRequest)
-			return
+// updateQueue re-reads the queue and saves update's result while holding
+// the queue lock, so tickets queued meanwhile aren't lost.
+func updateQueue(update func([]QueuedTicket) []QueuedTicket) error {
+	unlock, err := lockQueue()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	queue, err := loadQueue()
+	if err != nil {
+		return err
+	}
+
+	return saveQueue(update(queue))
+}
+
+// queueTicket stores a ticket for later creation, along with the label ids
+// and follow-up actions it needs.
+func queueTicket(ticket LinearTicket, labelMap map[string]string, options CreateOptions) error {
+	labelIds := make(map[string]string, len(ticket.Labels))
+	for _, label := range ticket.Labels {
+		if id, ok := labelMap[label]; ok {
+			labelIds[label] = id
 		}
+	}
 
-		code := query.Get("code")
-		if code == "" {
-			sendOAuthCallbackResult(resultCh, oauthCallbackResult{err: fmt.Errorf("OAuth callback did not include a code")})
-			http.Error(w, "OAuth callback did not include a code. You can close this tab.", http.StatusBadRequest)
-			return
+	// Give queued tickets an id up front so an interrupted flush can be
+	// rerun without creating duplicates
+	if ticket.ID == "" {
+		id, err := newUUID()
+		if err != nil {
+			return err
 		}
+		ticket.ID = id
+	}
 
-		sendOAuthCallbackResult(resultCh, oauthCallbackResult{code: code})
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		fmt.Fprint(w, "<html><body><h1>Linear sign-in complete</h1><p>You can close this tab and return to lnr.</p></body></html>")
+	queued := QueuedTicket{
+		Ticket:      ticket,
+		LabelIDs:    labelIds,
+		AttachURL:   options.AttachURL,
+		AttachTitle: options.AttachTitle,
+		Blocks:      options.Blocks,
+		BlockedBy:   options.BlockedBy,
+		QueuedAt:    time.Now(),
+	}
+
+	return updateQueue(func(queue []QueuedTicket) []QueuedTicket {
+		return append(queue, queued)
 	})
 }
 
-func sendOAuthCallbackResult(resultCh chan<- oauthCallbackResult, result oauthCallbackResult) {
-	select {
-	case resultCh <- result:
-	default:
+func queueTicketOrExit(ticket LinearTicket, labelMap map[string]string, options CreateOptions) {
+	if err := queueTicket(ticket, labelMap, options); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error queueing ticket: %v\n", err)
+		os.Exit(1)
+	}
+	if !quietOutput {
+		fmt.Printf(successSymbol+" Queued %q; it will be created once you're back online\n", ticket.Title)
 	}
 }
 
-func shutdownOAuthServer(server *http.Server) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-	_ = server.Shutdown(ctx)
+// IdempotencyRecord is the issue id reserved for an --idempotency-key.
+type IdempotencyRecord struct {
+	IssueID   string    `json:"issueId"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
-func registerOAuthClient(callbackURL, scopes string) (OAuthClientRegistrationResponse, error) {
-	payload := map[string]interface{}{
-		"client_name":                "lnr",
-		"client_uri":                 "https://github.com/dkarter/lnr",
-		"redirect_uris":              []string{callbackURL},
-		"grant_types":                []string{"authorization_code", "refresh_token"},
-		"response_types":             []string{"code"},
-		"scope":                      scopes,
-		"token_endpoint_auth_method": "none",
+func newUUID() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
 	}
+	data[6] = data[6]&0x0f | 0x40 // version 4
+	data[8] = data[8]&0x3f | 0x80 // RFC 4122 variant
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return OAuthClientRegistrationResponse{}, err
-	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:]), nil
+}
 
-	req, err := http.NewRequest("POST", linearOAuthRegistrationURL, bytes.NewBuffer(jsonData))
+// issueIDForKey returns the issue id reserved for key, reserving a new one
+// the first time the key is seen. Keys expire after idempotencyKeyTTL.
+func issueIDForKey(key string) (string, error) {
+	// Without the lock, two runs could each reserve an id and the last write
+	// would drop the other's, so a retry with that key would mint a new one
+	unlock, err := lockCacheFile(idempotencyKeysLockFile, idempotencyKeysFile)
 	if err != nil {
-		return OAuthClientRegistrationResponse{}, err
+		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	defer unlock()
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return OAuthClientRegistrationResponse{}, err
+	path := filepath.Join(getCacheDir(), idempotencyKeysFile)
+	records := make(map[string]IdempotencyRecord)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", idempotencyKeysFile, err)
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return OAuthClientRegistrationResponse{}, err
+	for k, record := range records {
+		if time.Since(record.CreatedAt) > idempotencyKeyTTL {
+			delete(records, k)
+		}
+	}
+	if record, ok := records[key]; ok {
+		return record.IssueID, nil
 	}
 
-	var client OAuthClientRegistrationResponse
-	if len(body) > 0 {
-		_ = json.Unmarshal(body, &client)
+	issueId, err := newUUID()
+	if err != nil {
+		return "", err
 	}
+	records[key] = IdempotencyRecord{IssueID: issueId, CreatedAt: time.Now()}
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return OAuthClientRegistrationResponse{}, oauthResponseError("OAuth client registration failed", body, client.Error, client.ErrorDescription)
+	if err := os.MkdirAll(getCacheDir(), 0755); err != nil {
+		return "", err
 	}
-	if client.Error != "" {
-		return OAuthClientRegistrationResponse{}, oauthResponseError("OAuth client registration failed", body, client.Error, client.ErrorDescription)
+	jsonData, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return "", err
 	}
-	if client.ClientID == "" {
-		return OAuthClientRegistrationResponse{}, fmt.Errorf("OAuth client registration response did not include client_id")
+	if err := writeFileAtomic(path, jsonData, 0600); err != nil {
+		return "", err
 	}
 
-	return client, nil
+	return issueId, nil
 }
 
-func buildAuthorizationURL(clientID, callbackURL, scopes, state, codeVerifier string) (string, error) {
-	authorizeURL, err := url.Parse(linearOAuthAuthorizeURL)
-	if err != nil {
-		return "", err
+// applyIdempotencyKey gives ticket the id reserved for --idempotency-key.
+func applyIdempotencyKey(apiKey string, ticket *LinearTicket, options CreateOptions) {
+	if options.IdempotencyKey == "" {
+		return
 	}
-
-	query := authorizeURL.Query()
-	query.Set("client_id", clientID)
-	query.Set("redirect_uri", callbackURL)
-	query.Set("response_type", "code")
-	query.Set("scope", scopes)
-	query.Set("state", state)
-	query.Set("code_challenge", codeChallenge(codeVerifier))
-	query.Set("code_challenge_method", "S256")
-	if linearOAuthResource != "" {
-		query.Set("resource", linearOAuthResource)
+	if err := requireAPIKey(apiKey, "--idempotency-key"); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+		os.Exit(1)
 	}
-	authorizeURL.RawQuery = query.Encode()
 
-	return authorizeURL.String(), nil
+	issueId, err := issueIDForKey(options.IdempotencyKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error reserving issue id: %v\n", err)
+		os.Exit(1)
+	}
+	ticket.ID = issueId
 }
 
-func exchangeOAuthCode(clientID, code, callbackURL, codeVerifier, scopes string) (OAuthTokenResponse, error) {
-	form := url.Values{}
-	form.Set("grant_type", "authorization_code")
-	form.Set("code", code)
-	form.Set("redirect_uri", callbackURL)
-	form.Set("client_id", clientID)
-	form.Set("code_verifier", codeVerifier)
-	form.Set("scope", scopes)
-	if linearOAuthResource != "" {
-		form.Set("resource", linearOAuthResource)
+func clearConfig() error {
+	configDir := getConfigDir()
+	if _, err := os.Stat(configDir); os.IsNotExist(err) {
+		return nil
 	}
-
-	return fetchOAuthAccessToken(form)
+	return os.RemoveAll(configDir)
 }
 
-func refreshOAuthAccessToken(clientID, refreshToken, scopes string) (OAuthTokenResponse, error) {
-	form := url.Values{}
-	form.Set("grant_type", "refresh_token")
-	form.Set("refresh_token", refreshToken)
-	form.Set("client_id", clientID)
-	form.Set("scope", scopes)
-	if linearOAuthResource != "" {
-		form.Set("resource", linearOAuthResource)
+func resetData() error {
+	if err := clearCache(); err != nil {
+		return err
 	}
 
-	return fetchOAuthAccessToken(form)
+	return clearConfig()
 }
 
-func fetchOAuthAccessToken(form url.Values) (OAuthTokenResponse, error) {
-	req, err := http.NewRequest("POST", linearOAuthTokenURL, strings.NewReader(form.Encode()))
+// resetDataPaths lists what resetData removes, for --dry-run.
+func resetDataPaths() ([]string, error) {
+	paths, err := cacheFilesToClear()
 	if err != nil {
-		return OAuthTokenResponse{}, err
+		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return OAuthTokenResponse{}, err
+	entries, err := os.ReadDir(getConfigDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		paths = append(paths, filepath.Join(getConfigDir(), entry.Name()))
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return OAuthTokenResponse{}, err
+	return paths, nil
+}
+
+// runReset clears cached data and saved defaults, or with dryRun only lists
+// the files it would remove.
+func runReset(dryRun bool) {
+	if dryRun {
+		paths, err := resetDataPaths()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error reading data: %v\n", err)
+			os.Exit(1)
+		}
+		if len(paths) == 0 {
+			fmt.Println("Nothing to remove")
+			return
+		}
+		fmt.Printf("Would remove %d files and directories:\n", len(paths))
+		for _, path := range paths {
+			fmt.Println("  " + path)
+		}
+		return
 	}
 
-	var token OAuthTokenResponse
-	if len(body) > 0 {
-		_ = json.Unmarshal(body, &token)
+	if err := resetData(); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error clearing data: %v\n", err)
+		os.Exit(1)
 	}
+	fmt.Println(successSymbol + " Data cleared successfully")
+}
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return OAuthTokenResponse{}, oauthResponseError("OAuth token request failed", body, token.Error, token.ErrorDescription)
+// linearAPIKey returns LINEAR_API_KEY, or the contents of the file named by
+// LINEAR_API_KEY_FILE (for secrets managers and Docker secrets) when it's
+// unset.
+func linearAPIKey() (string, error) {
+	if apiKey := os.Getenv("LINEAR_API_KEY"); apiKey != "" {
+		return apiKey, nil
 	}
-	if token.Error != "" {
-		return OAuthTokenResponse{}, oauthResponseError("OAuth token request failed", body, token.Error, token.ErrorDescription)
+
+	path := os.Getenv("LINEAR_API_KEY_FILE")
+	if path == "" {
+		return "", nil
 	}
-	if token.AccessToken == "" {
-		return OAuthTokenResponse{}, fmt.Errorf("OAuth token response did not include access_token")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading LINEAR_API_KEY_FILE: %w", err)
 	}
-	if token.TokenType == "" {
-		token.TokenType = "Bearer"
+	apiKey := strings.TrimSpace(string(data))
+	if apiKey == "" {
+		return "", fmt.Errorf("LINEAR_API_KEY_FILE %s is empty", path)
 	}
 
-	return token, nil
+	return apiKey, nil
 }
 
-func oauthResponseError(prefix string, body []byte, errorCode, description string) error {
-	if description != "" {
-		return fmt.Errorf("%s: %s", prefix, description)
-	}
-	if errorCode != "" {
-		return fmt.Errorf("%s: %s", prefix, errorCode)
-	}
-	return fmt.Errorf("%s: %s", prefix, strings.TrimSpace(string(body)))
+// Viewer is the user the credentials belong to and their organization.
+type Viewer struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organizationId"`
+	URLKey         string `json:"urlKey"`
 }
 
-func randomURLSafeString(byteCount int) (string, error) {
-	data := make([]byte, byteCount)
-	if _, err := rand.Read(data); err != nil {
-		return "", err
-	}
-
-	return base64.RawURLEncoding.EncodeToString(data), nil
-}
+// viewer is filled in by getLinearAuthHeader. It backs "me" as an assignee
+// and the workspace part of issue URLs; a zero Viewer means it's unknown.
+var viewer Viewer
 
-func codeChallenge(verifier string) string {
-	sum := sha256.Sum256([]byte(verifier))
-	return base64.RawURLEncoding.EncodeToString(sum[:])
-}
+// workspaceID is the Linear organization the credentials belong to. Saved
+// defaults are kept per workspace so switching API keys doesn't pre-select
+// another workspace's teams and labels. Empty means the shared defaults.
+var workspaceID string
 
-func openURL(rawURL string) error {
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
-	case "darwin":
-		cmd = exec.Command("open", rawURL)
-	case "linux":
-		cmd = exec.Command("xdg-open", rawURL)
-	default:
-		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
-	}
+func getLinearAuthHeader(ctx context.Context) string {
+	apiKey := linearAuthHeader(ctx)
+	viewer = loadViewer(ctx, apiKey)
+	workspaceID = viewer.OrganizationID
 
-	return cmd.Run()
+	return apiKey
 }
 
-func callMCPTool(authHeader, name string, arguments map[string]interface{}) ([]byte, error) {
-	requestBody := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      1,
-		"method":  "tools/call",
-		"params": map[string]interface{}{
-			"name":      name,
-			"arguments": arguments,
-		},
+// loadViewer returns the viewer for the credentials, cached for a week under
+// a hash of them. When the lookup fails, an expired cached viewer is used, so
+// a network blip doesn't switch to the shared defaults; OAuth sessions and
+// lookups with nothing cached return a zero Viewer.
+func loadViewer(ctx context.Context, apiKey string) Viewer {
+	if apiKey == "" {
+		return Viewer{}
+	}
+	if _, ok := splitMCPAuthHeader(apiKey); ok {
+		return Viewer{}
 	}
 
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, err
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(apiKey)))[:16]
+	cacheKey := "viewer-" + hash
+	if cached, found := loadTypedFromCache[Viewer](cacheKey, viewerCacheTTL); found {
+		return cached
 	}
 
-	req, err := http.NewRequest("POST", linearOAuthResource, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
+	loaded, err := fetchViewer(ctx, apiKey)
+	if err != nil || loaded.ID == "" {
+		if stale, found := loadTypedFromCache[Viewer](cacheKey, noCacheExpiration); found {
+			return stale
+		}
+		// Before the viewer was cached, only the workspace id was
+		if id, found := loadTypedFromCache[string]("workspace-"+hash, noCacheExpiration); found {
+			return Viewer{OrganizationID: id}
+		}
+		return Viewer{}
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-	req.Header.Set("Authorization", authHeader)
+	saveToCache(cacheKey, loaded)
+	os.Remove(getCachePath("workspace-" + hash))
 
-	resp, err := http.DefaultClient.Do(req)
+	return loaded
+}
+
+// fetchViewer looks up the user the credentials belong to. It always uses the
+// primary key, since rotated keys may belong to other users.
+func fetchViewer(ctx context.Context, apiKey string) (Viewer, error) {
+	result, err := makeLinearRequest(withPrimaryAPIKey(ctx), apiKey, `query Viewer { viewer { id name organization { id urlKey } } }`, nil)
 	if err != nil {
-		return nil, err
+		return Viewer{}, err
 	}
-	defer resp.Body.Close()
+	data, _ := result["data"].(map[string]interface{})
+	viewerData, _ := data["viewer"].(map[string]interface{})
+	organization, _ := viewerData["organization"].(map[string]interface{})
 
-	body, err := io.ReadAll(resp.Body)
+	return Viewer{
+		ID:             getString(viewerData, "id"),
+		Name:           getString(viewerData, "name"),
+		OrganizationID: getString(organization, "id"),
+		URLKey:         getString(organization, "urlKey"),
+	}, nil
+}
+
+func linearAuthHeader(ctx context.Context) string {
+	apiKey, err := linearAPIKey()
 	if err != nil {
-		return nil, err
+		fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+		os.Exit(1)
 	}
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return nil, fmt.Errorf("Linear MCP error: %s", strings.TrimSpace(string(body)))
+	if apiKey != "" {
+		return apiKey
 	}
 
-	data, err := extractSSEData(body)
-	if err != nil {
-		return nil, err
+	accessToken := os.Getenv("LINEAR_OAUTH_ACCESS_TOKEN")
+	if accessToken != "" {
+		return bearerAuthHeader(accessToken)
 	}
 
-	var mcpResponse MCPResponse
-	if err := json.Unmarshal(data, &mcpResponse); err != nil {
-		return nil, err
-	}
-	if mcpResponse.Error != nil {
-		return nil, fmt.Errorf("Linear MCP error: %s", mcpResponse.Error.Message)
-	}
+	scopes := oauthScopes()
 
-	for _, content := range mcpResponse.Result.Content {
-		if content.Type == "text" && content.Text != "" {
-			return []byte(content.Text), nil
+	if offlineMode {
+		// Only the auth mode matters offline; never refresh or log in
+		if cache, found := loadOAuthTokenCache(scopes); found {
+			return mcpAuthHeader(cache.AccessToken)
 		}
+		return ""
 	}
 
-	return nil, fmt.Errorf("Linear MCP response did not include text content")
-}
-
-func extractSSEData(body []byte) ([]byte, error) {
-	text := string(body)
-	if strings.TrimSpace(text) == "" {
-		return nil, fmt.Errorf("empty Linear MCP response")
-	}
+	if cache, found := loadOAuthTokenCache(scopes); found {
+		if cache.ExpiresAt.After(time.Now().Add(oauthTokenRefreshSkew)) {
+			return mcpAuthHeader(cache.AccessToken)
+		}
 
-	var dataLines []string
-	for _, line := range strings.Split(text, "\n") {
-		if strings.HasPrefix(line, "data: ") {
-			dataLines = append(dataLines, strings.TrimPrefix(line, "data: "))
+		if cache.RefreshToken != "" && cache.ClientID != "" {
+			token, err := refreshOAuthAccessToken(ctx, cache.ClientID, cache.RefreshToken, scopes)
+			if err == nil {
+				if err := saveOAuthToken(cache.ClientID, scopes, token, cache.RefreshToken); err == nil {
+					return mcpAuthHeader(token.AccessToken)
+				}
+			}
+			fmt.Fprintln(os.Stderr, "Cached Linear OAuth token expired; starting a new browser login.")
 		}
 	}
-	if len(dataLines) == 0 {
-		return body, nil
+
+	token, err := runDCRLogin(ctx, scopes)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error signing in to Linear: %v\n", err)
+		fmt.Fprintln(os.Stderr, "\nYou can still use a personal API key instead:")
+		fmt.Fprintln(os.Stderr, "  export LINEAR_API_KEY='your-api-key'")
+		os.Exit(1)
 	}
 
-	return []byte(strings.Join(dataLines, "\n")), nil
+	return mcpAuthHeader(token.AccessToken)
 }
 
-func fetchMCPTeams(authHeader string) ([]Team, error) {
-	var teamList []Team
-	var cursor string
-	for {
-		arguments := map[string]interface{}{"limit": 250}
-		if cursor != "" {
-			arguments["cursor"] = cursor
-		}
+func oauthScopes() string {
+	scopes := os.Getenv("LINEAR_OAUTH_SCOPES")
+	if scopes == "" {
+		return defaultOAuthScopes
+	}
 
-		data, err := callMCPTool(authHeader, "list_teams", arguments)
-		if err != nil {
-			return nil, err
-		}
+	return scopes
+}
 
-		var page MCPPage[Team]
-		if err := json.Unmarshal(data, &page); err != nil {
-			return nil, err
-		}
-		teamList = append(teamList, page.Teams...)
-		if !page.HasNextPage || page.Cursor == "" {
-			break
-		}
-		cursor = page.Cursor
+func bearerAuthHeader(token string) string {
+	if strings.HasPrefix(strings.ToLower(token), "bearer ") {
+		return token
 	}
 
-	return teamList, nil
+	return "Bearer " + token
 }
 
-func fetchMCPTeamLabels(authHeader, teamID string) ([]Label, error) {
-	var labelList []Label
-	var cursor string
-	for {
-		arguments := map[string]interface{}{"team": teamID, "limit": 250}
-		if cursor != "" {
-			arguments["cursor"] = cursor
-		}
-
-		data, err := callMCPTool(authHeader, "list_issue_labels", arguments)
-		if err != nil {
-			return nil, err
-		}
+func mcpAuthHeader(token string) string {
+	return mcpAuthHeaderPrefix + bearerAuthHeader(token)
+}
 
-		var page MCPPage[Label]
-		if err := json.Unmarshal(data, &page); err != nil {
-			return nil, err
-		}
-		labelList = append(labelList, page.Labels...)
-		if !page.HasNextPage || page.Cursor == "" {
-			break
-		}
-		cursor = page.Cursor
+func splitMCPAuthHeader(authHeader string) (string, bool) {
+	if !strings.HasPrefix(authHeader, mcpAuthHeaderPrefix) {
+		return authHeader, false
 	}
 
-	return labelList, nil
+	return strings.TrimPrefix(authHeader, mcpAuthHeaderPrefix), true
 }
 
-func fetchMCPTeamUsers(authHeader, teamID string) ([]User, error) {
-	var userList []User
-	var cursor string
-	for {
-		arguments := map[string]interface{}{"team": teamID, "limit": 250}
-		if cursor != "" {
-			arguments["cursor"] = cursor
-		}
+func loadOAuthTokenCache(scopes string) (OAuthTokenCache, bool) {
+	data, err := os.ReadFile(getCachePath(oauthTokenCacheKey))
+	if err != nil {
+		return OAuthTokenCache{}, false
+	}
 
-		data, err := callMCPTool(authHeader, "list_users", arguments)
-		if err != nil {
-			return nil, err
-		}
+	var cache OAuthTokenCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return OAuthTokenCache{}, false
+	}
 
-		var page MCPPage[User]
-		if err := json.Unmarshal(data, &page); err != nil {
-			return nil, err
-		}
-		userList = append(userList, page.Users...)
-		if !page.HasNextPage || page.Cursor == "" {
-			break
-		}
-		cursor = page.Cursor
+	if cache.Scope != scopes || cache.AccessToken == "" {
+		return OAuthTokenCache{}, false
 	}
 
-	return userList, nil
+	return cache, true
 }
 
-func fetchMCPWorkflowStates(authHeader, teamID string) ([]WorkflowState, error) {
-	data, err := callMCPTool(authHeader, "list_issue_statuses", map[string]interface{}{"team": teamID})
-	if err != nil {
-		return nil, err
+func saveOAuthToken(clientID, scopes string, token OAuthTokenResponse, fallbackRefreshToken string) error {
+	refreshToken := token.RefreshToken
+	if refreshToken == "" {
+		refreshToken = fallbackRefreshToken
 	}
 
-	var states []WorkflowState
-	if err := json.Unmarshal(data, &states); err != nil {
-		return nil, err
+	expiresIn := token.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = int64((24 * time.Hour).Seconds())
 	}
 
-	return states, nil
+	return saveOAuthTokenCache(OAuthTokenCache{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		RefreshToken: refreshToken,
+		Scope:        scopes,
+		ClientID:     clientID,
+		ExpiresAt:    time.Now().Add(time.Duration(expiresIn) * time.Second),
+	})
 }
 
-func fetchMCPTeamIssues(authHeader, teamID string) ([]Issue, error) {
-	var issueList []Issue
-	var cursor string
-	for {
-		arguments := map[string]interface{}{"team": teamID, "limit": 250}
-		if cursor != "" {
-			arguments["cursor"] = cursor
-		}
+func saveOAuthTokenCache(cache OAuthTokenCache) error {
+	jsonData, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
 
-		data, err := callMCPTool(authHeader, "list_issues", arguments)
-		if err != nil {
-			return nil, err
-		}
+	return writeFileAtomic(getCachePath(oauthTokenCacheKey), jsonData, 0600)
+}
 
-		var page MCPPage[MCPIssue]
-		if err := json.Unmarshal(data, &page); err != nil {
-			return nil, err
-		}
-		for _, issue := range page.Issues {
-			issueList = append(issueList, Issue{
-				Identifier: issue.ID,
-				BranchName: issue.GitBranchName,
-				Title:      issue.Title,
-				URL:        issue.URL,
-			})
-		}
-		if !page.HasNextPage || page.Cursor == "" {
-			break
-		}
-		cursor = page.Cursor
+func clearOAuthTokenCache() error {
+	err := os.Remove(getCachePath(oauthTokenCacheKey))
+	if os.IsNotExist(err) {
+		return nil
 	}
+	return err
+}
 
-	return issueList, nil
+type oauthCallbackResult struct {
+	code string
+	err  error
 }
 
-func createLinearTicketWithMCP(authHeader string, ticket LinearTicket) (CreatedIssue, error) {
-	arguments := map[string]interface{}{
-		"title": ticket.Title,
-		"team":  ticket.TeamId,
+func runDCRLogin(ctx context.Context, scopes string) (OAuthTokenResponse, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return OAuthTokenResponse{}, err
 	}
-	if ticket.Description != "" {
-		arguments["description"] = ticket.Description
+
+	callbackURL := fmt.Sprintf("http://%s/oauth/callback", listener.Addr().String())
+	client, err := registerOAuthClient(ctx, callbackURL, scopes)
+	if err != nil {
+		listener.Close()
+		return OAuthTokenResponse{}, err
 	}
-	if ticket.Estimate != "" && ticket.Estimate != "0" {
-		if estimate, err := strconv.Atoi(ticket.Estimate); err == nil {
-			arguments["estimate"] = estimate
+
+	state, err := randomURLSafeString(32)
+	if err != nil {
+		listener.Close()
+		return OAuthTokenResponse{}, err
+	}
+	codeVerifier, err := randomURLSafeString(64)
+	if err != nil {
+		listener.Close()
+		return OAuthTokenResponse{}, err
+	}
+
+	resultCh := make(chan oauthCallbackResult, 1)
+	server := &http.Server{Handler: oauthCallbackHandler(state, resultCh)}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			select {
+			case resultCh <- oauthCallbackResult{err: err}:
+			default:
+			}
 		}
+	}()
+
+	authURL, err := buildAuthorizationURL(client.ClientID, callbackURL, scopes, state, codeVerifier)
+	if err != nil {
+		shutdownOAuthServer(server)
+		return OAuthTokenResponse{}, err
 	}
-	if len(ticket.Labels) > 0 {
-		arguments["labels"] = ticket.Labels
+
+	fmt.Fprintln(os.Stderr, "Opening Linear sign-in in your browser...")
+	fmt.Fprintln(os.Stderr, authURL)
+	if err := openURL(authURL); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open browser automatically: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Open the URL above to continue.")
 	}
-	if ticket.AssigneeId != "" {
-		arguments["assignee"] = ticket.AssigneeId
+
+	var result oauthCallbackResult
+	select {
+	case result = <-resultCh:
+	case <-time.After(5 * time.Minute):
+		shutdownOAuthServer(server)
+		return OAuthTokenResponse{}, fmt.Errorf("timed out waiting for OAuth callback")
+	case <-ctx.Done():
+		shutdownOAuthServer(server)
+		return OAuthTokenResponse{}, ctx.Err()
 	}
-	if ticket.StatusId != "" {
-		arguments["state"] = ticket.StatusId
+	shutdownOAuthServer(server)
+
+	if result.err != nil {
+		return OAuthTokenResponse{}, result.err
 	}
 
-	data, err := callMCPTool(authHeader, "save_issue", arguments)
+	token, err := exchangeOAuthCode(ctx, client.ClientID, result.code, callbackURL, codeVerifier, scopes)
 	if err != nil {
-		return CreatedIssue{}, err
+		return OAuthTokenResponse{}, err
 	}
 
-	var issue MCPIssue
-	if err := json.Unmarshal(data, &issue); err != nil {
-		return CreatedIssue{}, err
-	}
-	if issue.ID == "" {
-		return CreatedIssue{}, fmt.Errorf("Linear MCP response did not include issue id")
+	if err := saveOAuthToken(client.ClientID, scopes, token, ""); err != nil {
+		return OAuthTokenResponse{}, err
 	}
 
-	return CreatedIssue{
-		Identifier: issue.ID,
-		BranchName: issue.GitBranchName,
-		Title:      issue.Title,
-		URL:        issue.URL,
-	}, nil
+	return token, nil
 }
 
-func loadUserSelections() UserSelections {
-	configPath := getConfigPath(userSelectionsConfigFile)
-	data, err := os.ReadFile(configPath)
-	if err == nil {
-		var selections UserSelections
-		if err := json.Unmarshal(data, &selections); err == nil {
-			return selections
+func oauthCallbackHandler(expectedState string, resultCh chan<- oauthCallbackResult) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/callback" {
+			http.NotFound(w, r)
+			return
 		}
-	}
 
-	if selections, found := loadTypedFromCache[UserSelections](userSelectionsCacheKey, noCacheExpiration); found {
-		_ = saveUserSelections(selections)
-		return selections
-	}
+		query := r.URL.Query()
+		if errorCode := query.Get("error"); errorCode != "" {
+			description := query.Get("error_description")
+			if description == "" {
+				description = errorCode
+			}
+			sendOAuthCallbackResult(resultCh, oauthCallbackResult{err: fmt.Errorf("OAuth authorization failed: %s", description)})
+			http.Error(w, "Linear authorization failed. You can close this tab.", http.StatusBadRequest)
+			return
+		}
 
-	return UserSelections{}
-}
+		if query.Get("state") != expectedState {
+			sendOAuthCallbackResult(resultCh, oauthCallbackResult{err: fmt.Errorf("OAuth state mismatch")})
+			http.Error(w, "OAuth state mismatch. You can close this tab.", http.StatusBadRequest)
+			return
+		}
 
-func saveUserSelections(selections UserSelections) error {
-	jsonData, err := json.MarshalIndent(selections, "", "  ")
-	if err != nil {
-		return err
-	}
+		code := query.Get("code")
+		if code == "" {
+			sendOAuthCallbackResult(resultCh, oauthCallbackResult{err: fmt.Errorf("OAuth callback did not include a code")})
+			http.Error(w, "OAuth callback did not include a code. You can close this tab.", http.StatusBadRequest)
+			return
+		}
 
-	return os.WriteFile(getConfigPath(userSelectionsConfigFile), jsonData, 0644)
+		sendOAuthCallbackResult(resultCh, oauthCallbackResult{code: code})
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<html><body><h1>Linear sign-in complete</h1><p>You can close this tab and return to lnr.</p></body></html>")
+	})
 }
 
-func fallbackBranchName(issue CreatedIssue) string {
-	if issue.BranchName != "" {
-		return issue.BranchName
+func sendOAuthCallbackResult(resultCh chan<- oauthCallbackResult, result oauthCallbackResult) {
+	select {
+	case resultCh <- result:
+	default:
 	}
-
-	return strings.ToLower(issue.Identifier)
 }
 
-func getString(data map[string]interface{}, key string) string {
-	if val, ok := data[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
-		}
-	}
-	return ""
+func shutdownOAuthServer(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
 }
 
-func makeLinearRequest(apiKey, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+func registerOAuthClient(ctx context.Context, callbackURL, scopes string) (OAuthClientRegistrationResponse, error) {
 	payload := map[string]interface{}{
-		"query":     query,
-		"variables": variables,
+		"client_name":                "lnr",
+		"client_uri":                 "https://github.com/dkarter/lnr",
+		"redirect_uris":              []string{callbackURL},
+		"grant_types":                []string{"authorization_code", "refresh_token"},
+		"response_types":             []string{"code"},
+		"scope":                      scopes,
+		"token_endpoint_auth_method": "none",
 	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, err
+		return OAuthClientRegistrationResponse{}, err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.linear.app/graphql", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", linearOAuthRegistrationURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, err
+		return OAuthClientRegistrationResponse{}, err
 	}
-
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", apiKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return OAuthClientRegistrationResponse{}, err
 	}
 	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OAuthClientRegistrationResponse{}, err
 	}
 
-	if errors, ok := result["errors"].([]interface{}); ok && len(errors) > 0 {
-		return nil, fmt.Errorf("Linear API error: %v", errors)
+	var client OAuthClientRegistrationResponse
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &client)
 	}
 
-	return result, nil
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return OAuthClientRegistrationResponse{}, oauthResponseError("OAuth client registration failed", body, client.Error, client.ErrorDescription)
+	}
+	if client.Error != "" {
+		return OAuthClientRegistrationResponse{}, oauthResponseError("OAuth client registration failed", body, client.Error, client.ErrorDescription)
+	}
+	if client.ClientID == "" {
+		return OAuthClientRegistrationResponse{}, fmt.Errorf("OAuth client registration response did not include client_id")
+	}
+
+	return client, nil
 }
 
-func fetchTeamLabels(apiKey, teamId string) ([]Label, error) {
-	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		return fetchMCPTeamLabels(authHeader, teamId)
+func buildAuthorizationURL(clientID, callbackURL, scopes, state, codeVerifier string) (string, error) {
+	authorizeURL, err := url.Parse(linearOAuthAuthorizeURL)
+	if err != nil {
+		return "", err
 	}
 
-	var labelList []Label
-	var after string
-
-	for {
-		query := `
-			query TeamLabels($teamId: String!, $after: String) {
-				team(id: $teamId) {
-					labels(first: 50, after: $after) {
-						nodes {
-							id
-							name
-						}
-						pageInfo {
-							hasNextPage
-							endCursor
-						}
-					}
-				}
-			}
-		`
+	query := authorizeURL.Query()
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", callbackURL)
+	query.Set("response_type", "code")
+	query.Set("scope", scopes)
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	if linearOAuthResource != "" {
+		query.Set("resource", linearOAuthResource)
+	}
+	authorizeURL.RawQuery = query.Encode()
 
-		variables := map[string]interface{}{"teamId": teamId}
-		if after != "" {
-			variables["after"] = after
-		}
+	return authorizeURL.String(), nil
+}
 
-		result, err := makeLinearRequest(apiKey, query, variables)
-		if err != nil {
-			return nil, err
-		}
+func exchangeOAuthCode(ctx context.Context, clientID, code, callbackURL, codeVerifier, scopes string) (OAuthTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", callbackURL)
+	form.Set("client_id", clientID)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("scope", scopes)
+	if linearOAuthResource != "" {
+		form.Set("resource", linearOAuthResource)
+	}
 
-		data := result["data"].(map[string]interface{})
-		team := data["team"].(map[string]interface{})
-		labels := team["labels"].(map[string]interface{})
-		nodes := labels["nodes"].([]interface{})
-		pageInfo := labels["pageInfo"].(map[string]interface{})
+	return fetchOAuthAccessToken(ctx, form)
+}
 
-		for _, node := range nodes {
-			label := node.(map[string]interface{})
-			labelList = append(labelList, Label{
-				ID:   label["id"].(string),
-				Name: label["name"].(string),
-			})
-		}
-
-		hasNextPage := pageInfo["hasNextPage"].(bool)
-		if !hasNextPage {
-			break
-		}
-
-		if endCursor, ok := pageInfo["endCursor"].(string); ok {
-			after = endCursor
-		} else {
-			break
-		}
+func refreshOAuthAccessToken(ctx context.Context, clientID, refreshToken, scopes string) (OAuthTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", clientID)
+	form.Set("scope", scopes)
+	if linearOAuthResource != "" {
+		form.Set("resource", linearOAuthResource)
 	}
 
-	return labelList, nil
+	return fetchOAuthAccessToken(ctx, form)
 }
 
-func fetchTeams(apiKey string) ([]Team, error) {
-	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		return fetchMCPTeams(authHeader)
+func fetchOAuthAccessToken(ctx context.Context, form url.Values) (OAuthTokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", linearOAuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return OAuthTokenResponse{}, err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	var teamList []Team
-	var after string
-
-	for {
-		query := `
-			query Teams($after: String) {
-				teams(first: 50, after: $after) {
-					nodes {
-						id
-						name
-					}
-					pageInfo {
-						hasNextPage
-						endCursor
-					}
-				}
-			}
-		`
-
-		variables := map[string]interface{}{}
-		if after != "" {
-			variables["after"] = after
-		}
-
-		result, err := makeLinearRequest(apiKey, query, variables)
-		if err != nil {
-			return nil, err
-		}
-
-		data := result["data"].(map[string]interface{})
-		teams := data["teams"].(map[string]interface{})
-		nodes := teams["nodes"].([]interface{})
-		pageInfo := teams["pageInfo"].(map[string]interface{})
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return OAuthTokenResponse{}, err
+	}
+	defer resp.Body.Close()
 
-		for _, node := range nodes {
-			team := node.(map[string]interface{})
-			teamList = append(teamList, Team{
-				ID:   team["id"].(string),
-				Name: team["name"].(string),
-			})
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OAuthTokenResponse{}, err
+	}
 
-		hasNextPage := pageInfo["hasNextPage"].(bool)
-		if !hasNextPage {
-			break
-		}
+	var token OAuthTokenResponse
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &token)
+	}
 
-		if endCursor, ok := pageInfo["endCursor"].(string); ok {
-			after = endCursor
-		} else {
-			break
-		}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return OAuthTokenResponse{}, oauthResponseError("OAuth token request failed", body, token.Error, token.ErrorDescription)
+	}
+	if token.Error != "" {
+		return OAuthTokenResponse{}, oauthResponseError("OAuth token request failed", body, token.Error, token.ErrorDescription)
+	}
+	if token.AccessToken == "" {
+		return OAuthTokenResponse{}, fmt.Errorf("OAuth token response did not include access_token")
+	}
+	if token.TokenType == "" {
+		token.TokenType = "Bearer"
 	}
 
-	return teamList, nil
+	return token, nil
 }
 
-func fetchTeamInfo(apiKey, teamId string) (*Team, error) {
-	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		teams, err := fetchMCPTeams(authHeader)
-		if err != nil {
-			return nil, err
-		}
-		for _, team := range teams {
-			if team.ID == teamId {
-				return &team, nil
-			}
-		}
-		return nil, fmt.Errorf("team not found: %s", teamId)
+func oauthResponseError(prefix string, body []byte, errorCode, description string) error {
+	if description != "" {
+		return fmt.Errorf("%s: %s", prefix, description)
 	}
-
-	query := `
-		query Team($teamId: String!) {
-			team(id: $teamId) {
-				id
-				name
-			}
-		}
-	`
-
-	result, err := makeLinearRequest(apiKey, query, map[string]interface{}{"teamId": teamId})
-	if err != nil {
-		return nil, err
+	if errorCode != "" {
+		return fmt.Errorf("%s: %s", prefix, errorCode)
 	}
-
-	data := result["data"].(map[string]interface{})
-	team := data["team"].(map[string]interface{})
-
-	return &Team{
-		ID:   team["id"].(string),
-		Name: team["name"].(string),
-	}, nil
+	return fmt.Errorf("%s: %s", prefix, strings.TrimSpace(string(body)))
 }
 
-func fetchTeamUsers(apiKey, teamId string) ([]User, error) {
-	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		return fetchMCPTeamUsers(authHeader, teamId)
+func randomURLSafeString(byteCount int) (string, error) {
+	data := make([]byte, byteCount)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
 	}
 
-	var userList []User
-	var after string
-
-	for {
-		query := `
-			query TeamUsers($teamId: String!, $after: String) {
-				team(id: $teamId) {
-					organization {
-						users(first: 50, after: $after) {
-							nodes {
-								id
-								name
-								email
-							}
-							pageInfo {
-								hasNextPage
-								endCursor
-							}
-						}
-					}
-				}
-			}
-		`
-
-		variables := map[string]interface{}{"teamId": teamId}
-		if after != "" {
-			variables["after"] = after
-		}
-
-		result, err := makeLinearRequest(apiKey, query, variables)
-		if err != nil {
-			return nil, err
-		}
-
-		data := result["data"].(map[string]interface{})
-		team := data["team"].(map[string]interface{})
-		org := team["organization"].(map[string]interface{})
-		users := org["users"].(map[string]interface{})
-		nodes := users["nodes"].([]interface{})
-		pageInfo := users["pageInfo"].(map[string]interface{})
-
-		for _, node := range nodes {
-			user := node.(map[string]interface{})
-			userList = append(userList, User{
-				ID:    user["id"].(string),
-				Name:  user["name"].(string),
-				Email: user["email"].(string),
-			})
-		}
-
-		hasNextPage := pageInfo["hasNextPage"].(bool)
-		if !hasNextPage {
-			break
-		}
-
-		if endCursor, ok := pageInfo["endCursor"].(string); ok {
-			after = endCursor
-		} else {
-			break
-		}
-	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
 
-	return userList, nil
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
 
-func fetchWorkflowStates(apiKey, teamId string) ([]WorkflowState, error) {
-	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		return fetchMCPWorkflowStates(authHeader, teamId)
+func openURL(rawURL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	case "linux":
+		cmd = exec.Command("xdg-open", rawURL)
+	default:
+		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
 
-	var stateList []WorkflowState
-	var after string
-
-	for {
-		query := `
-			query TeamWorkflowStates($teamId: String!, $after: String) {
-				team(id: $teamId) {
-					states(first: 50, after: $after) {
-						nodes {
-							id
-							name
-							type
-						}
-						pageInfo {
-							hasNextPage
-							endCursor
-						}
-					}
-				}
-			}
-		`
-
-		variables := map[string]interface{}{"teamId": teamId}
-		if after != "" {
-			variables["after"] = after
-		}
+	return cmd.Run()
+}
 
-		result, err := makeLinearRequest(apiKey, query, variables)
-		if err != nil {
-			return nil, err
-		}
+// refreshedMCPAuth maps a bearer header Linear rejected mid-session to the one a
+// refresh produced, so calls still holding the stale header skip straight to it.
+var refreshedMCPAuth sync.Map
 
-		data := result["data"].(map[string]interface{})
-		team := data["team"].(map[string]interface{})
-		states := team["states"].(map[string]interface{})
-		nodes := states["nodes"].([]interface{})
-		pageInfo := states["pageInfo"].(map[string]interface{})
+// callMCPTool calls an MCP tool, refreshing the cached OAuth token and retrying
+// once when Linear rejects it as expired.
+func callMCPTool(ctx context.Context, authHeader, name string, arguments map[string]interface{}) ([]byte, error) {
+	if fresh, ok := refreshedMCPAuth.Load(authHeader); ok {
+		authHeader = fresh.(string)
+	}
 
-		for _, node := range nodes {
-			state := node.(map[string]interface{})
-			stateList = append(stateList, WorkflowState{
-				ID:   state["id"].(string),
-				Name: state["name"].(string),
-				Type: state["type"].(string),
-			})
-		}
+	data, err := sendMCPToolCall(ctx, authHeader, name, arguments)
+	if !errors.Is(err, errUnauthorized) {
+		return data, err
+	}
 
-		hasNextPage := pageInfo["hasNextPage"].(bool)
-		if !hasNextPage {
-			break
-		}
+	fresh, ok := refreshMCPAuth(ctx, authHeader)
+	if !ok {
+		return nil, unauthorizedError(mcpAuthHeaderPrefix + authHeader)
+	}
+	refreshedMCPAuth.Store(authHeader, fresh)
 
-		if endCursor, ok := pageInfo["endCursor"].(string); ok {
-			after = endCursor
-		} else {
-			break
-		}
+	data, err = sendMCPToolCall(ctx, fresh, name, arguments)
+	if errors.Is(err, errUnauthorized) {
+		return nil, unauthorizedError(mcpAuthHeaderPrefix + fresh)
 	}
 
-	return stateList, nil
+	return data, err
 }
 
-func loadTeams(apiKey string) ([]Team, error) {
-	if teams, found := loadTypedFromCache[[]Team]("teams", noCacheExpiration); found {
-		return teams, nil
+// refreshMCPAuth trades the cached refresh token for a new access token after
+// authHeader was rejected. Another lnr process may already have refreshed the
+// cache, in which case its token is used as is.
+func refreshMCPAuth(ctx context.Context, authHeader string) (string, bool) {
+	scopes := oauthScopes()
+	cache, found := loadOAuthTokenCache(scopes)
+	if !found {
+		return "", false
+	}
+	if cached := bearerAuthHeader(cache.AccessToken); cached != authHeader {
+		return cached, true
+	}
+	if cache.RefreshToken == "" || cache.ClientID == "" {
+		return "", false
 	}
 
-	teams, err := fetchTeams(apiKey)
+	token, err := refreshOAuthAccessToken(ctx, cache.ClientID, cache.RefreshToken, scopes)
 	if err != nil {
-		return nil, err
+		return "", false
+	}
+	if err := saveOAuthToken(cache.ClientID, scopes, token, cache.RefreshToken); err != nil {
+		fmt.Fprintf(os.Stderr, infoSymbol+" Could not save the refreshed Linear token: %v\n", err)
 	}
-	saveToCache("teams", teams)
 
-	return teams, nil
+	return bearerAuthHeader(token.AccessToken), true
 }
 
-func loadTeamLabels(apiKey, teamId string) ([]Label, error) {
-	if labels, found := loadTypedFromCache[[]Label]("labels-"+teamId, noCacheExpiration); found {
-		return labels, nil
+func sendMCPToolCall(ctx context.Context, authHeader, name string, arguments map[string]interface{}) ([]byte, error) {
+	if offlineMode {
+		return nil, errOffline
+	}
+
+	requestBody := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]interface{}{
+			"name":      name,
+			"arguments": arguments,
+		},
 	}
 
-	labels, err := fetchTeamLabels(apiKey, teamId)
+	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, err
 	}
-	saveToCache("labels-"+teamId, labels)
-
-	return labels, nil
-}
 
-func loadTeamUsers(apiKey, teamId string) ([]User, error) {
-	if users, found := loadTypedFromCache[[]User]("users-"+teamId, noCacheExpiration); found {
-		return users, nil
+	req, err := http.NewRequestWithContext(ctx, "POST", linearOAuthResource, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Authorization", authHeader)
 
-	users, err := fetchTeamUsers(apiKey, teamId)
+	logRequest(name, arguments)
+	start := time.Now()
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	saveToCache("users-"+teamId, users)
+	defer resp.Body.Close()
 
-	return users, nil
-}
-
-func loadWorkflowStates(apiKey, teamId string) ([]WorkflowState, error) {
-	if states, found := loadTypedFromCache[[]WorkflowState]("states-"+teamId, noCacheExpiration); found {
-		return states, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	logResponse(name, resp.StatusCode, time.Since(start), body)
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errUnauthorized
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("Linear MCP error: %s", strings.TrimSpace(string(body)))
 	}
 
-	states, err := fetchWorkflowStates(apiKey, teamId)
+	data, err := extractSSEData(body)
 	if err != nil {
 		return nil, err
 	}
-	saveToCache("states-"+teamId, states)
 
-	return states, nil
+	var mcpResponse MCPResponse
+	if err := json.Unmarshal(data, &mcpResponse); err != nil {
+		return nil, err
+	}
+	if mcpResponse.Error != nil {
+		return nil, fmt.Errorf("Linear MCP error: %s", mcpResponse.Error.Message)
+	}
+
+	for _, content := range mcpResponse.Result.Content {
+		if content.Type == "text" && content.Text != "" {
+			return []byte(content.Text), nil
+		}
+	}
+
+	return nil, fmt.Errorf("Linear MCP response did not include text content")
 }
 
-func fetchTeamIssues(apiKey, teamId string) ([]Issue, error) {
-	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		return fetchMCPTeamIssues(authHeader, teamId)
+func extractSSEData(body []byte) ([]byte, error) {
+	text := string(body)
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("empty Linear MCP response")
 	}
 
-	var issues []Issue
-	var after string
+	var dataLines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "data: ") {
+			dataLines = append(dataLines, strings.TrimPrefix(line, "data: "))
+		}
+	}
+	if len(dataLines) == 0 {
+		return body, nil
+	}
 
-	for len(issues) < 250 {
-		query := `
-			query TeamIssues($teamId: String!, $after: String) {
-				team(id: $teamId) {
-					issues(first: 50, after: $after, orderBy: updatedAt) {
-						nodes {
-							identifier
-							title
-							branchName
-							url
-						}
-						pageInfo {
-							hasNextPage
-							endCursor
-						}
-					}
-				}
-			}
-		`
+	return []byte(strings.Join(dataLines, "\n")), nil
+}
 
-		variables := map[string]interface{}{"teamId": teamId}
-		if after != "" {
-			variables["after"] = after
+func fetchMCPTeams(ctx context.Context, authHeader string) ([]Team, error) {
+	var teamList []Team
+	var cursor string
+	for {
+		arguments := map[string]interface{}{"limit": 250}
+		if cursor != "" {
+			arguments["cursor"] = cursor
 		}
 
-		result, err := makeLinearRequest(apiKey, query, variables)
+		data, err := callMCPTool(ctx, authHeader, "list_teams", arguments)
 		if err != nil {
 			return nil, err
 		}
 
-		data := result["data"].(map[string]interface{})
-		team := data["team"].(map[string]interface{})
-		issueConnection := team["issues"].(map[string]interface{})
-		nodes := issueConnection["nodes"].([]interface{})
-		pageInfo := issueConnection["pageInfo"].(map[string]interface{})
+		var page MCPPage[Team]
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		teamList = append(teamList, page.Teams...)
+		if !page.HasNextPage || page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	return teamList, nil
+}
 
-		for _, node := range nodes {
-			issue := node.(map[string]interface{})
-			issues = append(issues, Issue{
-				Identifier: issue["identifier"].(string),
-				Title:      issue["title"].(string),
-				BranchName: getString(issue, "branchName"),
-				URL:        issue["url"].(string),
-			})
+func fetchMCPTeamLabels(ctx context.Context, authHeader, teamID string) ([]Label, error) {
+	var labelList []Label
+	var cursor string
+	for {
+		arguments := map[string]interface{}{"team": teamID, "limit": 250}
+		if cursor != "" {
+			arguments["cursor"] = cursor
 		}
 
-		if hasNextPage := pageInfo["hasNextPage"].(bool); !hasNextPage {
-			break
+		data, err := callMCPTool(ctx, authHeader, "list_issue_labels", arguments)
+		if err != nil {
+			return nil, err
 		}
 
-		if endCursor, ok := pageInfo["endCursor"].(string); ok {
-			after = endCursor
-		} else {
+		var page MCPPage[Label]
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		labelList = append(labelList, page.Labels...)
+		if !page.HasNextPage || page.Cursor == "" {
 			break
 		}
+		cursor = page.Cursor
 	}
 
-	return issues, nil
+	return labelList, nil
 }
 
-func getEstimateOptions(estimateType int) []huh.Option[string] {
-	switch estimateType {
-	case 0: // No estimates
-		return []huh.Option[string]{
-			{Key: "No estimate", Value: "0"},
+func fetchMCPTeamUsers(ctx context.Context, authHeader, teamID string) ([]User, error) {
+	var userList []User
+	var cursor string
+	for {
+		arguments := map[string]interface{}{"team": teamID, "limit": 250}
+		if cursor != "" {
+			arguments["cursor"] = cursor
 		}
-	case 1: // T-shirt sizes
-		return []huh.Option[string]{
-			{Key: "XS - Extra Small", Value: "1"},
-			{Key: "S - Small", Value: "2"},
-			{Key: "M - Medium", Value: "3"},
-			{Key: "L - Large", Value: "5"},
-			{Key: "XL - Extra Large", Value: "8"},
+
+		data, err := callMCPTool(ctx, authHeader, "list_users", arguments)
+		if err != nil {
+			return nil, err
 		}
-	case 2: // Fibonacci
-		return []huh.Option[string]{
-			{Key: "1", Value: "1"},
-			{Key: "2", Value: "2"},
-			{Key: "3", Value: "3"},
-			{Key: "5", Value: "5"},
-			{Key: "8", Value: "8"},
-			{Key: "13", Value: "13"},
-			{Key: "21", Value: "21"},
+
+		var page MCPPage[User]
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
 		}
-	default: // Linear's default (story points)
-		return []huh.Option[string]{
-			{Key: "0 - No estimate", Value: "0"},
-			{Key: "1 - Small (< 1 day)", Value: "1"},
-			{Key: "2 - Medium (1-2 days)", Value: "2"},
-			{Key: "3 - Large (3-5 days)", Value: "3"},
-			{Key: "5 - Extra Large (1+ weeks)", Value: "5"},
-			{Key: "8 - Epic (2+ weeks)", Value: "8"},
+		userList = append(userList, page.Users...)
+		if !page.HasNextPage || page.Cursor == "" {
+			break
 		}
+		cursor = page.Cursor
 	}
+
+	return userList, nil
 }
 
-func teamOptions(teams []Team) []huh.Option[string] {
-	options := make([]huh.Option[string], len(teams))
-	for i, team := range teams {
-		options[i] = huh.Option[string]{Key: team.Name, Value: team.ID}
+func fetchMCPWorkflowStates(ctx context.Context, authHeader, teamID string) ([]WorkflowState, error) {
+	data, err := callMCPTool(ctx, authHeader, "list_issue_statuses", map[string]interface{}{"team": teamID})
+	if err != nil {
+		return nil, err
 	}
 
-	return options
-}
-
-func labelOptions(labels []Label) ([]huh.Option[string], map[string]string) {
-	options := make([]huh.Option[string], len(labels))
-	labelMap := make(map[string]string)
-	for i, label := range labels {
-		options[i] = huh.Option[string]{Key: label.Name, Value: label.Name}
-		labelMap[label.Name] = label.ID
+	var states []WorkflowState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
 	}
 
-	return options, labelMap
+	return states, nil
 }
 
-func findTeam(teams []Team, teamId string) *Team {
-	for _, team := range teams {
-		if team.ID == teamId {
-			return &team
+func fetchMCPTeamIssues(ctx context.Context, authHeader, teamID string) ([]Issue, error) {
+	var issueList []Issue
+	var cursor string
+	for {
+		arguments := map[string]interface{}{"team": teamID, "limit": 250}
+		if cursor != "" {
+			arguments["cursor"] = cursor
 		}
-	}
 
-	return nil
-}
+		data, err := callMCPTool(ctx, authHeader, "list_issues", arguments)
+		if err != nil {
+			return nil, err
+		}
 
-func requireDefaultTeam(selections UserSelections) string {
-	if selections.TeamId == "" {
-		fmt.Println("❌ No default team set")
-		fmt.Println("Run `lnr set-team` first")
-		os.Exit(1)
+		var page MCPPage[MCPIssue]
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			issueList = append(issueList, Issue{
+				Identifier: issue.ID,
+				BranchName: issue.GitBranchName,
+				Title:      issue.Title,
+				URL:        issue.URL,
+			})
+		}
+		if !page.HasNextPage || page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
 	}
 
-	return selections.TeamId
+	return issueList, nil
 }
 
-func runSetTeam(apiKey string) {
-	teams, err := loadTeams(apiKey)
-	if err != nil {
-		fmt.Printf("❌ Error fetching teams: %v\n", err)
-		os.Exit(1)
+func createLinearTicketWithMCP(ctx context.Context, authHeader string, ticket LinearTicket) (CreatedIssue, error) {
+	arguments := map[string]interface{}{
+		"title": ticket.Title,
+		"team":  ticket.TeamId,
 	}
-
-	selections := loadUserSelections()
-	selectedTeamId := selections.TeamId
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Default Team").
-				Description("Filter and select the team to use for quick actions").
-				Options(teamOptions(teams)...).
-				Filtering(true).
-				Value(&selectedTeamId),
-		),
-	)
-
-	if err := form.Run(); err != nil {
-		fmt.Println("Team selection cancelled or error:", err)
-		os.Exit(1)
+	if ticket.Description != "" {
+		arguments["description"] = ticket.Description
 	}
-
-	if selections.TeamId != selectedTeamId {
-		selections.AssigneeId = ""
-		selections.Labels = nil
-		selections.StatusId = ""
+	if ticket.Estimate != "" && ticket.Estimate != "0" {
+		if estimate, err := strconv.Atoi(ticket.Estimate); err == nil {
+			arguments["estimate"] = estimate
+		}
 	}
-	selections.TeamId = selectedTeamId
-	if err := saveUserSelections(selections); err != nil {
-		fmt.Printf("❌ Error saving default team: %v\n", err)
-		os.Exit(1)
+	if len(ticket.Labels) > 0 {
+		arguments["labels"] = ticket.Labels
 	}
-
-	selectedTeam := findTeam(teams, selectedTeamId)
-	if selectedTeam != nil {
-		fmt.Printf("✅ Default team set to %s\n", selectedTeam.Name)
-		return
+	if ticket.AssigneeId != "" {
+		arguments["assignee"] = ticket.AssigneeId
+	}
+	if ticket.StatusId != "" {
+		arguments["state"] = ticket.StatusId
+	}
+	if ticket.Priority != "" && ticket.Priority != "0" {
+		if priority, err := strconv.Atoi(ticket.Priority); err == nil {
+			arguments["priority"] = priority
+		}
 	}
-	fmt.Println("✅ Default team saved")
-}
-
-func runSetLabels(apiKey string) {
-	selections := loadUserSelections()
-	teamId := requireDefaultTeam(selections)
 
-	labels, err := loadTeamLabels(apiKey, teamId)
+	data, err := callMCPTool(ctx, authHeader, "save_issue", arguments)
 	if err != nil {
-		fmt.Printf("❌ Error fetching labels: %v\n", err)
-		os.Exit(1)
+		return CreatedIssue{}, err
 	}
 
-	selectedLabels := selections.Labels
-	options, _ := labelOptions(labels)
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewMultiSelect[string]().
-				Title("Default Labels").
-				Description("Filter and select labels to apply in quick mode").
-				Options(options...).
-				Filtering(true).
-				Value(&selectedLabels).
-				Limit(4),
-		),
-	)
-
-	if err := form.Run(); err != nil {
-		fmt.Println("Label selection cancelled or error:", err)
-		os.Exit(1)
+	var issue MCPIssue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return CreatedIssue{}, err
 	}
-
-	selections.Labels = selectedLabels
-	if err := saveUserSelections(selections); err != nil {
-		fmt.Printf("❌ Error saving default labels: %v\n", err)
-		os.Exit(1)
+	if issue.ID == "" {
+		return CreatedIssue{}, fmt.Errorf("Linear MCP response did not include issue id")
 	}
 
-	if len(selectedLabels) == 0 {
-		fmt.Println("✅ Default labels cleared")
-		return
-	}
-	fmt.Printf("✅ Default labels set to %s\n", strings.Join(selectedLabels, ", "))
+	return CreatedIssue{
+		Identifier: issue.ID,
+		BranchName: issue.GitBranchName,
+		Title:      issue.Title,
+		URL:        issue.URL,
+	}, nil
 }
 
-func runSetEstimate() {
-	selections := loadUserSelections()
-	selectedEstimate := selections.Estimate
-	estimateOptions := getEstimateOptions(1)
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Default Estimate").
-				Description("Select the estimate to apply in quick mode").
-				Options(estimateOptions...).
-				Value(&selectedEstimate),
-		),
-	)
-
-	if err := form.Run(); err != nil {
-		fmt.Println("Estimate selection cancelled or error:", err)
-		os.Exit(1)
+// userSelectionsPath is defaults.json, or defaults-<workspace>.json once the
+// workspace is known.
+func userSelectionsPath() string {
+	if workspaceID == "" {
+		return getConfigPath(userSelectionsConfigFile)
 	}
 
-	selections.Estimate = selectedEstimate
-	if err := saveUserSelections(selections); err != nil {
-		fmt.Printf("❌ Error saving default estimate: %v\n", err)
-		os.Exit(1)
+	name := strings.TrimSuffix(userSelectionsConfigFile, ".json") + "-" + workspaceID + ".json"
+	return getConfigPath(name)
+}
+
+func loadUserSelections() UserSelections {
+	configPath := userSelectionsPath()
+
+	// The first workspace seen starts from a copy of the defaults saved
+	// before they were kept per workspace. The shared file stays for OAuth
+	// and offline runs, which don't know the workspace.
+	if legacyPath := getConfigPath(userSelectionsConfigFile); configPath != legacyPath {
+		pattern := strings.TrimSuffix(legacyPath, ".json") + "-*.json"
+		if others, _ := filepath.Glob(pattern); len(others) == 0 {
+			if data, err := os.ReadFile(legacyPath); err == nil {
+				_ = writeFileAtomic(configPath, data, 0644)
+			}
+		}
 	}
 
-	for _, option := range estimateOptions {
-		if option.Value == selectedEstimate {
-			fmt.Printf("✅ Default estimate set to %s\n", option.Key)
-			return
+	data, err := os.ReadFile(configPath)
+	if err == nil {
+		var selections UserSelections
+		if err := json.Unmarshal(data, &selections); err == nil {
+			return selections
 		}
 	}
-	fmt.Println("✅ Default estimate saved")
-}
 
-func runSetStatus(apiKey string) {
-	selections := loadUserSelections()
-	teamId := requireDefaultTeam(selections)
+	if selections, found := loadTypedFromCache[UserSelections](userSelectionsCacheKey, noCacheExpiration); found {
+		_ = saveUserSelections(selections)
+		return selections
+	}
+
+	return UserSelections{}
+}
 
-	workflowStates, err := loadWorkflowStates(apiKey, teamId)
+func saveUserSelections(selections UserSelections) error {
+	jsonData, err := json.MarshalIndent(selections, "", "  ")
 	if err != nil {
-		fmt.Printf("❌ Error fetching workflow states: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	statusOptions := make([]huh.Option[string], len(workflowStates)+1)
-	statusOptions[0] = huh.Option[string]{Key: "No default status", Value: ""}
-	for i, state := range workflowStates {
-		statusOptions[i+1] = huh.Option[string]{Key: state.Name, Value: state.ID}
+	return writeFileAtomic(userSelectionsPath(), jsonData, 0644)
+}
+
+func loadConfig() Config {
+	var config Config
+	data, err := os.ReadFile(getConfigPath(configFile))
+	if err != nil {
+		return config
 	}
+	_ = json.Unmarshal(data, &config)
 
-	selectedStatusId := selections.StatusId
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Default Status").
-				Description("Select the status to apply to new issues").
-				Options(statusOptions...).
-				Filtering(true).
-				Value(&selectedStatusId),
-		),
-	)
+	return config
+}
 
-	if err := form.Run(); err != nil {
-		fmt.Println("Status selection cancelled or error:", err)
-		os.Exit(1)
+func appendHistory(entry HistoryEntry) error {
+	if loadConfig().DisableHistory {
+		return nil
 	}
 
-	selections.StatusId = selectedStatusId
-	if err := saveUserSelections(selections); err != nil {
-		fmt.Printf("❌ Error saving default status: %v\n", err)
-		os.Exit(1)
+	jsonData, err := json.Marshal(entry)
+	if err != nil {
+		return err
 	}
 
-	if selectedStatusId == "" {
-		fmt.Println("✅ Default status cleared")
-		return
+	historyPath := getConfigPath(historyFile)
+	if info, err := os.Stat(historyPath); err == nil && info.Size() >= maxHistoryFileSize {
+		if err := os.Rename(historyPath, historyPath+".1"); err != nil {
+			return err
+		}
 	}
 
-	for _, state := range workflowStates {
-		if state.ID == selectedStatusId {
-			fmt.Printf("✅ Default status set to %s\n", state.Name)
-			return
-		}
+	file, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
 	}
-	fmt.Println("✅ Default status saved")
+	defer file.Close()
+
+	_, err = file.Write(append(jsonData, '\n'))
+	return err
 }
 
-func runQuickCreate(apiKey, title string, jsonOutput bool) {
-	title = strings.TrimSpace(title)
-	if title == "" {
-		fmt.Println("❌ Title cannot be empty")
-		os.Exit(1)
+func recordHistory(issue CreatedIssue, teamId string) {
+	err := appendHistory(HistoryEntry{
+		Identifier: issue.Identifier,
+		Title:      issue.Title,
+		TeamId:     teamId,
+		URL:        issue.URL,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Failed to write history: %v\n", err)
 	}
 
-	selections := loadUserSelections()
-	teamId := requireDefaultTeam(selections)
-	labels, err := loadTeamLabels(apiKey, teamId)
-	if err != nil {
-		fmt.Printf("❌ Error fetching labels: %v\n", err)
-		os.Exit(1)
+	if err := appendAuditLog(os.Getenv("LNR_LOG_FILE"), issue, teamId); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Failed to write audit log: %v\n", err)
 	}
-	_, labelMap := labelOptions(labels)
+}
 
-	issue, err := createLinearTicket(apiKey, LinearTicket{
-		Title:      title,
-		TeamId:     teamId,
-		Labels:     selections.Labels,
-		Estimate:   selections.Estimate,
-		AssigneeId: selections.AssigneeId,
-		StatusId:   selections.StatusId,
-	}, labelMap)
-	if err != nil {
-		fmt.Printf("❌ Error creating ticket: %v\n", err)
-		os.Exit(1)
+// appendAuditLog appends a JSON line for the created issue to path. Unlike
+// history it's opt-in, never rotated, and records who filed the ticket.
+func appendAuditLog(path string, issue CreatedIssue, teamId string) error {
+	if path == "" {
+		return nil
 	}
 
-	branchName := fallbackBranchName(issue)
-	issue.BranchName = branchName
-	if jsonOutput {
-		jsonData, err := json.Marshal(issue)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Failed to encode JSON: %v\n", err)
-			os.Exit(1)
+	entry := AuditEntry{
+		Timestamp:  time.Now().UTC(),
+		Identifier: issue.Identifier,
+		TeamId:     teamId,
+		Title:      issue.Title,
+		URL:        issue.URL,
+		User:       auditUser(),
+	}
+	if teams, found := loadTypedFromCache[[]Team]("teams", noCacheExpiration); found {
+		for _, team := range teams {
+			if team.ID == teamId {
+				entry.Team = team.Key
+			}
 		}
+	}
 
-		fmt.Println(string(jsonData))
-		return
+	jsonData, err := json.Marshal(entry)
+	if err != nil {
+		return err
 	}
 
-	if err := clipboard.WriteAll(branchName); err != nil {
-		fmt.Println(branchName)
-		fmt.Fprintf(os.Stderr, "❌ Failed to copy to clipboard: %v\n", err)
-		return
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
 	}
+	defer file.Close()
 
-	fmt.Println(branchName)
+	_, err = file.Write(append(jsonData, '\n'))
+	return err
 }
 
-func runConfigure(apiKey string) {
-	fmt.Println("Configure default team, labels, estimate, and status")
-	runSetTeam(apiKey)
-	runSetLabels(apiKey)
-	runSetEstimate()
-	runSetStatus(apiKey)
+// auditUser is the local account that ran lnr, falling back to $USER.
+func auditUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+
+	return os.Getenv("USER")
 }
 
-func fallbackIssueBranchName(issue Issue) string {
-	if issue.BranchName != "" {
-		return issue.BranchName
+func loadHistory() ([]HistoryEntry, error) {
+	data, err := os.ReadFile(getConfigPath(historyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return strings.ToLower(issue.Identifier)
-}
+	var entries []HistoryEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
 
-func issueSearchScore(issue Issue, term string) int {
-	query := strings.ToLower(strings.TrimSpace(term))
-	if query == "" {
-		return 0
+		var entry HistoryEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
 	}
 
-	identifier := strings.ToLower(issue.Identifier)
-	title := strings.ToLower(issue.Title)
-	searchText := identifier + " " + title
-	if query == identifier {
-		return 1000
+	return entries, nil
+}
+
+func loadTicketTemplates() (map[string]TicketTemplate, error) {
+	data, err := os.ReadFile(getConfigPath(ticketTemplatesConfigFile))
+	if os.IsNotExist(err) {
+		return map[string]TicketTemplate{}, nil
 	}
-	if strings.Contains(identifier, query) {
-		return 900 + len(query)
+	if err != nil {
+		return nil, err
 	}
-	if strings.Contains(title, query) {
-		return 700 + len(query)
+
+	var templates map[string]TicketTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ticketTemplatesConfigFile, err)
 	}
-	if strings.Contains(searchText, query) {
-		return 600 + len(query)
+
+	return templates, nil
+}
+
+// loadDescriptionTemplate renders the markdown scaffold
+// ~/.config/lnr/descriptions/<name>.md.
+func loadDescriptionTemplate(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid description template name: %s", name)
 	}
 
-	score := 0
-	queryIndex := 0
-	for _, r := range searchText {
-		if queryIndex >= len(query) {
-			break
+	dir := filepath.Join(getConfigDir(), descriptionTemplatesDir)
+	content, err := os.ReadFile(filepath.Join(dir, name+".md"))
+	if os.IsNotExist(err) {
+		var available []string
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, entry := range entries {
+				if filepath.Ext(entry.Name()) == ".md" {
+					available = append(available, strings.TrimSuffix(entry.Name(), ".md"))
+				}
+			}
 		}
-		if byte(r) == query[queryIndex] {
-			score++
-			queryIndex++
+		if len(available) == 0 {
+			return "", fmt.Errorf("description template not found: %s (add it as %s)", name, filepath.Join(dir, name+".md"))
 		}
+		return "", fmt.Errorf("description template not found: %s (available: %s)", name, strings.Join(available, ", "))
 	}
-	if queryIndex != len(query) {
-		return 0
+	if err != nil {
+		return "", err
 	}
 
-	return score
+	return renderTemplateText(string(content), newTemplateData())
 }
 
-func findBestIssue(issues []Issue, term string) (Issue, bool) {
-	var bestIssue Issue
-	bestScore := 0
-	for _, issue := range issues {
-		score := issueSearchScore(issue, term)
-		if score > bestScore {
-			bestScore = score
-			bestIssue = issue
-		}
+func currentGitBranch() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", err
 	}
 
-	return bestIssue, bestScore > 0
+	return strings.TrimSpace(string(output)), nil
 }
 
-func outputIssue(issue Issue, jsonOutput bool) {
-	branchName := fallbackIssueBranchName(issue)
-	issue.BranchName = branchName
-	if jsonOutput {
-		jsonData, err := json.Marshal(issue)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Failed to encode JSON: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Println(string(jsonData))
-		return
+// currentRepoKey identifies the git repo in the working directory by its
+// origin remote, or returns "" outside a repo or without a remote.
+func currentRepoKey() string {
+	output, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
 	}
 
-	if err := clipboard.WriteAll(branchName); err != nil {
-		fmt.Println(branchName)
-		fmt.Fprintf(os.Stderr, "❌ Failed to copy to clipboard: %v\n", err)
-		return
+	return repoKey(strings.TrimSpace(string(output)))
+}
+
+// repoKey normalizes a git remote URL so the SSH and HTTPS forms of the same
+// repo match: git@github.com:org/repo.git becomes github.com/org/repo.
+func repoKey(remote string) string {
+	remote = strings.ToLower(strings.TrimSpace(remote))
+	if index := strings.Index(remote, "://"); index >= 0 {
+		remote = remote[index+3:]
+	} else if at := strings.Index(remote, "@"); at >= 0 {
+		remote = strings.Replace(remote[at+1:], ":", "/", 1)
+	}
+	if at := strings.Index(remote, "@"); at >= 0 && at < strings.Index(remote+"/", "/") {
+		remote = remote[at+1:]
 	}
 
-	fmt.Println(branchName)
+	return strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
 }
 
-func runIssueSearch(apiKey, searchTerm string, jsonOutput bool) {
-	selections := loadUserSelections()
-	teamId := requireDefaultTeam(selections)
+// repoDefaultTeam returns the team configured in repoTeams for the current
+// repo, else the team last used from it. It returns nil outside a repo or
+// when the team no longer exists, leaving the default team in charge.
+func repoDefaultTeam(teams []Team, selections UserSelections) *Team {
+	repo := currentRepoKey()
+	if repo == "" {
+		return nil
+	}
 
-	issues, err := fetchTeamIssues(apiKey, teamId)
-	if err != nil {
-		fmt.Printf("❌ Error fetching issues: %v\n", err)
-		os.Exit(1)
+	for remote, team := range loadConfig().RepoTeams {
+		if repoKey(remote) == repo {
+			return matchTeam(teams, team)
+		}
 	}
-	if len(issues) == 0 {
-		fmt.Println("No issues found for the default team")
-		return
+	if teamId := selections.RepoTeams[repo]; teamId != "" {
+		return findTeam(teams, teamId)
 	}
-	if searchTerm != "" {
-		issue, found := findBestIssue(issues, searchTerm)
-		if !found {
-			fmt.Fprintf(os.Stderr, "No issue matched %q\n", searchTerm)
-			os.Exit(1)
-		}
 
-		outputIssue(issue, jsonOutput)
-		return
+	return nil
+}
+
+func titleFromBranch(branch string) string {
+	if index := strings.LastIndex(branch, "/"); index >= 0 {
+		branch = branch[index+1:]
 	}
 
-	issueByKey := make(map[string]Issue, len(issues))
-	options := make([]huh.Option[string], len(issues))
-	for i, issue := range issues {
-		key := issue.Identifier + " " + issue.Title
-		issueByKey[key] = issue
-		options[i] = huh.Option[string]{Key: key, Value: key}
+	words := strings.FieldsFunc(branch, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	title := strings.Join(words, " ")
+	if title == "" {
+		return ""
 	}
 
-	selectedIssueKey := ""
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Issue").
-				Description("Filter issues from the default team").
-				Options(options...).
-				Filtering(true).
-				Value(&selectedIssueKey),
-		),
-	)
+	first, size := utf8.DecodeRuneInString(title)
+	return string(unicode.ToUpper(first)) + title[size:]
+}
 
-	if err := form.Run(); err != nil {
-		fmt.Println("Issue selection cancelled or error:", err)
-		os.Exit(1)
+func newTemplateData() TemplateData {
+	branch, _ := currentGitBranch()
+	return TemplateData{
+		Date:   time.Now().Format("2006-01-02"),
+		Branch: branch,
 	}
-
-	issue := issueByKey[selectedIssueKey]
-	outputIssue(issue, jsonOutput)
 }
 
-func runAuth(args []string) {
-	if len(args) == 0 || hasHelpArg(args) {
-		printAuthUsage()
-		return
+func renderTemplateText(text string, data TemplateData) (string, error) {
+	tmpl, err := template.New("ticket").Parse(text)
+	if err != nil {
+		return "", err
 	}
 
-	switch args[0] {
-	case "login":
-		if err := clearOAuthTokenCache(); err != nil {
-			fmt.Printf("❌ Error clearing saved OAuth token: %v\n", err)
-			os.Exit(1)
-		}
-		if _, err := runDCRLogin(oauthScopes()); err != nil {
-			fmt.Printf("❌ Error signing in to Linear: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println("✅ Linear OAuth token saved")
-	case "logout":
-		if err := clearOAuthTokenCache(); err != nil {
-			fmt.Printf("❌ Error clearing saved OAuth token: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println("✅ Linear OAuth token cleared")
-	default:
-		fmt.Printf("Unknown auth command: %s\n\n", args[0])
-		printAuthUsage()
-		os.Exit(1)
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", err
 	}
-}
 
-func isHelpArg(arg string) bool {
-	return arg == "help" || arg == "-h" || arg == "--help"
+	return rendered.String(), nil
 }
 
-func hasHelpArg(args []string) bool {
-	for _, arg := range args {
-		if isHelpArg(arg) {
-			return true
-		}
+func applyTicketTemplate(ticket *LinearTicket, tmpl TicketTemplate, data TemplateData) error {
+	titlePrefix, err := renderTemplateText(tmpl.TitlePrefix, data)
+	if err != nil {
+		return err
+	}
+	description, err := renderTemplateText(tmpl.Description, data)
+	if err != nil {
+		return err
 	}
 
-	return false
-}
+	ticket.Title = titlePrefix + ticket.Title
+	if description != "" {
+		ticket.Description = description
+	}
+	if len(tmpl.Labels) > 0 {
+		ticket.Labels = tmpl.Labels
+	}
+	if tmpl.Estimate != "" {
+		ticket.Estimate = tmpl.Estimate
+	}
+	if tmpl.Priority != "" {
+		ticket.Priority = tmpl.Priority
+	}
 
-func printQuickUsage() {
-	fmt.Println("Usage:")
-	fmt.Println("  lnr quick [--json] <title>")
-	fmt.Println("  lnr [--json] --quick <title>")
+	return nil
 }
 
-func printIssueUsage() {
-	fmt.Println("Usage:")
-	fmt.Println("  lnr issue [--json] [search term]")
-	fmt.Println("  lnr [--json] issue [search term]")
+func fallbackBranchName(issue CreatedIssue) string {
+	return issueBranchName(issue.Identifier, issue.Title, issue.BranchName)
 }
 
-func printCompletionUsage() {
-	fmt.Println("Usage:")
-	fmt.Println("  lnr completion bash")
-	fmt.Println("  lnr completion zsh")
+// BranchTemplateData is what a branchTemplate in config.json can use.
+type BranchTemplateData struct {
+	User       string
+	Identifier string
+	Title      string
+	Team       string
 }
 
-func printAuthUsage() {
-	fmt.Println("Usage:")
-	fmt.Println("  lnr auth login")
-	fmt.Println("  lnr auth logout")
+var branchSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+func branchSlug(text string) string {
+	return strings.Trim(branchSlugPattern.ReplaceAllString(strings.ToLower(text), "-"), "-")
 }
 
-func parseQuickArgs(args []string) (string, bool) {
-	var titleParts []string
-	jsonOutput := false
-	for _, arg := range args {
-		switch arg {
-		case "--json":
-			jsonOutput = true
-		default:
-			titleParts = append(titleParts, arg)
-		}
+// branchUser is the .User of a branchTemplate: a slug of the Linear user's
+// name, or the local account when the viewer is unknown (e.g. OAuth).
+func branchUser() string {
+	if user := branchSlug(viewer.Name); user != "" {
+		return user
 	}
 
-	return strings.Join(titleParts, " "), jsonOutput
+	return auditUser()
 }
 
-func parseIssueArgs(args []string) (string, bool) {
-	var searchParts []string
-	jsonOutput := false
-	for _, arg := range args {
-		switch arg {
-		case "--json":
-			jsonOutput = true
-		default:
-			searchParts = append(searchParts, arg)
+var branchTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"slug":  branchSlug,
+	"trunc": func(n int, text string) string {
+		if runes := []rune(text); len(runes) > n {
+			return strings.TrimRight(string(runes[:n]), "-")
+		}
+		return text
+	},
+}
+
+// issueBranchName renders the configured branchTemplate, falling back to
+// Linear's branch name and then the lowercased identifier.
+func issueBranchName(identifier, title, linearBranch string) string {
+	if branchTemplate := loadConfig().BranchTemplate; branchTemplate != "" {
+		branch, err := renderBranchTemplate(branchTemplate, identifier, title)
+		if err == nil {
+			return branch
 		}
+		fmt.Fprintf(os.Stderr, infoSymbol+" Ignoring branchTemplate in config.json: %v\n", err)
+	}
+	if linearBranch != "" {
+		return linearBranch
 	}
 
-	return strings.Join(searchParts, " "), jsonOutput
+	return strings.ToLower(identifier)
 }
 
-func printBashCompletion() {
-	fmt.Print(`_lnr_completion() {
-  local cur prev commands global_flags shells
-  COMPREPLY=()
-  cur="${COMP_WORDS[COMP_CWORD]}"
-  prev="${COMP_WORDS[COMP_CWORD-1]}"
-  commands="quick issue auth configure set-team set-labels set-estimate set-status completion reset help"
-  global_flags="--clear-cache --json --quick -h --help"
-  shells="bash zsh"
+func renderBranchTemplate(text, identifier, title string) (string, error) {
+	tmpl, err := template.New("branch").Funcs(branchTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
 
-  if [[ ${COMP_CWORD} -eq 1 ]]; then
-    COMPREPLY=( $(compgen -W "${commands} ${global_flags}" -- "${cur}") )
-    return 0
-  fi
+	team, _, _ := strings.Cut(identifier, "-")
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, BranchTemplateData{
+		User:       branchUser(),
+		Identifier: identifier,
+		Title:      title,
+		Team:       team,
+	}); err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(rendered.String())
+	if branch == "" {
+		return "", fmt.Errorf("template rendered an empty branch name")
+	}
 
-  case "${COMP_WORDS[1]}" in
-    quick)
-      COMPREPLY=( $(compgen -W "--json -h --help" -- "${cur}") )
-      return 0
-      ;;
-    issue)
-      COMPREPLY=( $(compgen -W "--json -h --help" -- "${cur}") )
-      return 0
-      ;;
-    auth)
-      COMPREPLY=( $(compgen -W "login logout -h --help" -- "${cur}") )
-      return 0
-      ;;
-    completion)
-      COMPREPLY=( $(compgen -W "${shells}" -- "${cur}") )
-      return 0
-      ;;
-  esac
+	return branch, nil
 }
 
-complete -F _lnr_completion lnr
-`)
+func getString(data map[string]interface{}, key string) string {
+	if val, ok := data[key]; ok {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return ""
 }
 
-func printZshCompletion() {
-	fmt.Print(`#compdef lnr
+var graphQLOperationPattern = regexp.MustCompile(`(?:query|mutation)\s+(\w+)`)
 
-_lnr() {
-  local -a commands
-  commands=(
-    'quick:Create a Linear issue from a title'
-    'issue:Find an issue in the default team'
-    'auth:Manage OAuth sign-in'
-    'configure:Configure default team, labels, estimate, and status'
-    'set-team:Set the default team'
-    'set-labels:Set default labels'
-    'set-estimate:Set the default estimate'
-    'set-status:Set the default status'
-    'completion:Generate shell completions'
-    'reset:Clear cached API data and saved defaults'
-    'help:Show help'
-  )
+func graphQLOperationName(query string) string {
+	if match := graphQLOperationPattern.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
 
-  case $words[2] in
-    quick)
-      _arguments '--json[Output JSON]' '-h[Show help]' '--help[Show help]' '*:title:'
-      ;;
-    issue)
-      _arguments '--json[Output JSON]' '-h[Show help]' '--help[Show help]' '*:search term:'
-      ;;
-    auth)
-      _arguments '1:auth command:(login logout)' '-h[Show help]' '--help[Show help]'
-      ;;
-    completion)
-      _arguments '1:shell:(bash zsh)'
-      ;;
-    *)
-      _arguments '--clear-cache[Clear cached API data and saved defaults]' '--json[Output JSON]' '--quick[Create a Linear issue from a title]' '1:command:->commands'
-      if [[ $state == commands ]]; then
-        _describe 'commands' commands
-      fi
-      ;;
-  esac
+	return "anonymous"
 }
 
-_lnr "$@"
-`)
+// logRequest writes request details to stderr when --verbose is set. Auth
+// headers are never logged.
+func logRequest(name string, variables interface{}) {
+	if verbosity < 1 {
+		return
+	}
+
+	jsonData, _ := json.Marshal(variables)
+	fmt.Fprintf(os.Stderr, requestSymbol+" %s %s\n", name, jsonData)
 }
 
-func runCompletion(shell string) {
-	switch shell {
-	case "bash":
-		printBashCompletion()
-	case "zsh":
-		printZshCompletion()
-	default:
-		printCompletionUsage()
-		os.Exit(1)
+func logResponse(name string, status int, elapsed time.Duration, body []byte) {
+	if verbosity < 1 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, responseSymbol+" %s %d (%s)\n", name, status, elapsed.Round(time.Millisecond))
+	if verbosity > 1 {
+		fmt.Fprintf(os.Stderr, "%s\n", body)
 	}
 }
 
-func main() {
-	// Parse command-line flags
-	clearCacheFlag := flag.Bool("clear-cache", false, "Clear cached API data and saved defaults")
-	quickTitleFlag := flag.String("quick", "", "Create a Linear issue from a title and print the branch name")
-	jsonOutputFlag := flag.Bool("json", false, "Output supported command result as JSON")
-	flag.Usage = func() {
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr quick [--json] <title>\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr issue [--json] [search term]\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr auth login|logout\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr configure\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-team\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-labels\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-estimate\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-status\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr completion bash|zsh\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "  lnr reset\n\n")
-		flag.PrintDefaults()
+// httpClient is shared by all Linear API and OAuth requests. It honors
+// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY unless --no-proxy is given.
+var httpClient = newHTTPClient(true)
+
+func newHTTPClient(useProxy bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if !useProxy {
+		transport.Proxy = nil
 	}
-	flag.Parse()
 
-	// Handle clear cache flag
-	if *clearCacheFlag {
-		if err := resetData(); err != nil {
-			fmt.Printf("❌ Error clearing data: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println("✅ Data cleared successfully")
-		return
+	return &http.Client{Transport: transport}
+}
+
+// apiKeyRotation round-robins through the configured apiKeys.
+var apiKeyRotation struct {
+	once sync.Once
+	keys []string
+	next atomic.Uint64
+}
+
+// apiKeyRotationContextKey marks a context whose requests may rotate through
+// the configured apiKeys.
+type apiKeyRotationContextKey struct{}
+
+// withAPIKeyRotation lets requests made with ctx rotate through the apiKeys in
+// config.json. Only bulk commands (--import, bulk status updates) opt in.
+func withAPIKeyRotation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiKeyRotationContextKey{}, true)
+}
+
+// withPrimaryAPIKey pins requests made with ctx to the primary key, for
+// identity lookups whose answer must belong to LINEAR_API_KEY itself.
+func withPrimaryAPIKey(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiKeyRotationContextKey{}, false)
+}
+
+// rotateAPIKey returns the key to send for a request made with ctx. When ctx
+// opts into rotation and apiKeys are configured, it round-robins through
+// apiKey followed by the apiKeys in config.json; otherwise it's apiKey.
+func rotateAPIKey(ctx context.Context, apiKey string) string {
+	if rotate, _ := ctx.Value(apiKeyRotationContextKey{}).(bool); !rotate {
+		return apiKey
 	}
-	if *quickTitleFlag != "" {
-		runQuickCreate(getLinearAuthHeader(), *quickTitleFlag, *jsonOutputFlag)
-		return
+	if _, ok := splitMCPAuthHeader(apiKey); ok {
+		return apiKey
 	}
 
-	args := flag.Args()
+	apiKeyRotation.once.Do(func() {
+		for _, key := range loadConfig().APIKeys {
+			if key = strings.TrimSpace(key); key != "" && key != apiKey && !slices.Contains(apiKeyRotation.keys, key) {
+				apiKeyRotation.keys = append(apiKeyRotation.keys, key)
+			}
+		}
+	})
+	keys := apiKeyRotation.keys
+	if len(keys) == 0 {
+		return apiKey
+	}
+
+	index := (apiKeyRotation.next.Add(1) - 1) % uint64(len(keys)+1)
+	if index == 0 {
+		return apiKey
+	}
+
+	return keys[index-1]
+}
+
+// requestMemo keeps GraphQL query results for the life of the process, so a
+// query repeated with the same key and variables (e.g. a team's states looked
+// up by both the form and a follow-up) hits the network once. Identical
+// queries in flight at the same time share one request.
+var requestMemo = struct {
+	sync.Mutex
+	entries map[string]*memoEntry
+}{entries: make(map[string]*memoEntry)}
+
+type memoEntry struct {
+	done   chan struct{}
+	result map[string]interface{}
+	err    error
+}
+
+// clearRequestMemo forgets every memoized query result.
+func clearRequestMemo() {
+	requestMemo.Lock()
+	requestMemo.entries = make(map[string]*memoEntry)
+	requestMemo.Unlock()
+}
+
+// makeLinearRequest runs a GraphQL request, answering repeated queries from
+// requestMemo. Callers must treat the result as read-only since it's shared.
+func makeLinearRequest(ctx context.Context, apiKey, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+	if strings.HasPrefix(strings.TrimSpace(query), "mutation") {
+		// A mutation can change what any query returns
+		clearRequestMemo()
+		return sendLinearRequest(ctx, apiKey, query, variables)
+	}
+
+	jsonData, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+	key := apiKey + "\x00" + query + "\x00" + string(jsonData)
+
+	requestMemo.Lock()
+	if entry, ok := requestMemo.entries[key]; ok {
+		requestMemo.Unlock()
+		select {
+		case <-entry.done:
+			return entry.result, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	entry := &memoEntry{done: make(chan struct{})}
+	requestMemo.entries[key] = entry
+	requestMemo.Unlock()
+
+	entry.result, entry.err = sendLinearRequest(ctx, apiKey, query, variables)
+	if entry.err != nil {
+		// Failures aren't kept, so a later call tries again
+		requestMemo.Lock()
+		if requestMemo.entries[key] == entry {
+			delete(requestMemo.entries, key)
+		}
+		requestMemo.Unlock()
+	}
+	close(entry.done)
+
+	return entry.result, entry.err
+}
+
+func sendLinearRequest(ctx context.Context, apiKey, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+	if offlineMode {
+		return nil, errOffline
+	}
+
+	payload := map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.linear.app/graphql", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", rotateAPIKey(ctx, apiKey))
+
+	operationName := graphQLOperationName(query)
+	logRequest(operationName, variables)
+	start := time.Now()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	logResponse(operationName, resp.StatusCode, time.Since(start), body)
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, unauthorizedError(apiKey)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	if graphQLErrors, ok := result["errors"].([]interface{}); ok && len(graphQLErrors) > 0 {
+		if isAuthenticationError(graphQLErrors) {
+			return nil, unauthorizedError(apiKey)
+		}
+		return nil, fmt.Errorf("Linear API error: %s", graphQLErrorMessage(graphQLErrors))
+	}
+
+	return result, nil
+}
+
+// isAuthenticationError reports whether Linear refused the request because of
+// the credentials; it answers those with 400 and an AUTHENTICATION_ERROR code.
+func isAuthenticationError(graphQLErrors []interface{}) bool {
+	for _, item := range graphQLErrors {
+		graphQLError, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if extensions, ok := graphQLError["extensions"].(map[string]interface{}); ok {
+			if code, _ := extensions["code"].(string); code == "AUTHENTICATION_ERROR" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// unauthorizedError tells the user how to fix rejected credentials for the auth mode in use.
+func unauthorizedError(authHeader string) error {
+	if _, ok := splitMCPAuthHeader(authHeader); ok {
+		return fmt.Errorf("%w: your Linear session expired or was revoked; run `lnr auth login` to sign in again", errUnauthorized)
+	}
+	if strings.HasPrefix(strings.ToLower(authHeader), "bearer ") {
+		return fmt.Errorf("%w: the OAuth access token expired or was revoked; refresh it and update LINEAR_OAUTH_ACCESS_TOKEN", errUnauthorized)
+	}
+
+	return fmt.Errorf("%w: check LINEAR_API_KEY or create a new key at https://linear.app/settings/api", errUnauthorized)
+}
+
+// graphQLErrorMessage joins the messages of GraphQL error objects, preferring
+// extensions.userPresentableMessage over the terser message field.
+func graphQLErrorMessage(graphQLErrors []interface{}) string {
+	var messages []string
+	for _, item := range graphQLErrors {
+		graphQLError, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		message, _ := graphQLError["message"].(string)
+		if extensions, ok := graphQLError["extensions"].(map[string]interface{}); ok {
+			if presentable, _ := extensions["userPresentableMessage"].(string); presentable != "" {
+				message = presentable
+			}
+		}
+		if message = strings.TrimSpace(message); message != "" && !slices.Contains(messages, message) {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 0 {
+		return fmt.Sprintf("%v", graphQLErrors)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// connectionPage picks a paged connection out of a GraphQL result by
+// following path from data, e.g. "team", "labels". It returns the nodes and
+// the cursor of the next page, empty on the last one. A response of another
+// shape is an error rather than a panic.
+func connectionPage(result map[string]interface{}, path ...string) ([]map[string]interface{}, string, error) {
+	connection, _ := result["data"].(map[string]interface{})
+	for _, key := range path {
+		connection, _ = connection[key].(map[string]interface{})
+	}
+	rawNodes, ok := connection["nodes"].([]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected response: no %s nodes", strings.Join(path, "."))
+	}
+
+	nodes := make([]map[string]interface{}, 0, len(rawNodes))
+	for _, rawNode := range rawNodes {
+		node, ok := rawNode.(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("unexpected response: malformed %s node", strings.Join(path, "."))
+		}
+		nodes = append(nodes, node)
+	}
+
+	pageInfo, _ := connection["pageInfo"].(map[string]interface{})
+	if hasNextPage, _ := pageInfo["hasNextPage"].(bool); !hasNextPage {
+		return nodes, "", nil
+	}
+
+	return nodes, getString(pageInfo, "endCursor"), nil
+}
+
+func fetchTeamLabels(ctx context.Context, apiKey, teamId string) ([]Label, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		return fetchMCPTeamLabels(ctx, authHeader, teamId)
+	}
+
+	var labelList []Label
+	var after string
+
+	for {
+		query := `
+			query TeamLabels($teamId: String!, $after: String) {
+				team(id: $teamId) {
+					labels(first: 50, after: $after) {
+						nodes {
+							id
+							name
+							color
+							isGroup
+							parent {
+								id
+								name
+							}
+						}
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{"teamId": teamId}
+		if after != "" {
+			variables["after"] = after
+		}
+
+		result, err := makeLinearRequest(ctx, apiKey, query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, next, err := connectionPage(result, "team", "labels")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, label := range nodes {
+			teamLabel := Label{
+				ID:    getString(label, "id"),
+				Name:  getString(label, "name"),
+				Color: getString(label, "color"),
+			}
+			teamLabel.IsGroup, _ = label["isGroup"].(bool)
+			if parent, ok := label["parent"].(map[string]interface{}); ok {
+				teamLabel.GroupID = getString(parent, "id")
+				teamLabel.GroupName = getString(parent, "name")
+			}
+			labelList = append(labelList, teamLabel)
+		}
+
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	return labelList, nil
+}
+
+func fetchTeams(ctx context.Context, apiKey string) ([]Team, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		return fetchMCPTeams(ctx, authHeader)
+	}
+
+	var teamList []Team
+	var after string
+
+	for {
+		query := `
+			query Teams($after: String) {
+				teams(first: 50, after: $after) {
+					nodes {
+						id
+						name
+						key
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{}
+		if after != "" {
+			variables["after"] = after
+		}
+
+		result, err := makeLinearRequest(ctx, apiKey, query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, next, err := connectionPage(result, "teams")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, team := range nodes {
+			teamList = append(teamList, Team{
+				ID:   getString(team, "id"),
+				Name: getString(team, "name"),
+				Key:  getString(team, "key"),
+			})
+		}
+
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	return teamList, nil
+}
+
+func fetchTeamInfo(ctx context.Context, apiKey, teamId string) (*Team, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		teams, err := fetchMCPTeams(ctx, authHeader)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			if team.ID == teamId {
+				return &team, nil
+			}
+		}
+		return nil, fmt.Errorf("team not found: %s", teamId)
+	}
+
+	query := `
+		query Team($teamId: String!) {
+			team(id: $teamId) {
+				id
+				name
+				key
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"teamId": teamId})
+	if err != nil {
+		return nil, err
+	}
+
+	data := result["data"].(map[string]interface{})
+	team := data["team"].(map[string]interface{})
+
+	return &Team{
+		ID:   team["id"].(string),
+		Name: team["name"].(string),
+		Key:  getString(team, "key"),
+	}, nil
+}
+
+// refreshTeam re-reads team from Linear so a renamed team doesn't keep its
+// cached name, updating the cached team list when it changed. Failures (and
+// --offline) keep the cached team.
+func refreshTeam(ctx context.Context, apiKey string, teams []Team, team Team) Team {
+	if offlineMode {
+		return team
+	}
+
+	fresh, err := fetchTeamInfo(ctx, apiKey, team.ID)
+	if err != nil || (fresh.Name == team.Name && fresh.Key == team.Key) {
+		return team
+	}
+	for i := range teams {
+		if teams[i].ID == fresh.ID {
+			teams[i] = *fresh
+		}
+	}
+	saveToCache("teams", teams)
+
+	return *fresh
+}
+
+func getFloat(data map[string]interface{}, key string) float64 {
+	if val, ok := data[key].(float64); ok {
+		return val
+	}
+	return 0
+}
+
+func getBool(data map[string]interface{}, key string) bool {
+	if val, ok := data[key].(bool); ok {
+		return val
+	}
+	return false
+}
+
+// requireAPIKey reports an error for features only available through the GraphQL API.
+func requireAPIKey(apiKey, feature string) error {
+	if _, ok := splitMCPAuthHeader(apiKey); ok {
+		return fmt.Errorf("%s requires a Linear API key (LINEAR_API_KEY)", feature)
+	}
+
+	return nil
+}
+
+// fetchTemplates lists the issue templates a team offers in Linear's UI.
+func fetchTemplates(ctx context.Context, apiKey, teamId string) ([]LinearTemplate, error) {
+	if err := requireAPIKey(apiKey, "Linear templates"); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query TeamTemplates($teamId: String!) {
+			team(id: $teamId) {
+				templates {
+					nodes {
+						id
+						name
+						type
+					}
+				}
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"teamId": teamId})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := connectionPage(result, "team", "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	var templateList []LinearTemplate
+	for _, template := range nodes {
+		if getString(template, "type") != "issue" {
+			continue
+		}
+		templateList = append(templateList, LinearTemplate{
+			ID:   getString(template, "id"),
+			Name: getString(template, "name"),
+		})
+	}
+
+	return templateList, nil
+}
+
+// fetchTeamProjects lists the team's projects that are still open.
+func fetchTeamProjects(ctx context.Context, apiKey, teamId string) ([]Project, error) {
+	if err := requireAPIKey(apiKey, "projects"); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query TeamProjects($teamId: String!) {
+			team(id: $teamId) {
+				projects(first: 100) {
+					nodes {
+						id
+						name
+						state
+					}
+				}
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"teamId": teamId})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := connectionPage(result, "team", "projects")
+	if err != nil {
+		return nil, err
+	}
+
+	var projectList []Project
+	for _, project := range nodes {
+		switch getString(project, "state") {
+		case "completed", "canceled":
+			continue
+		}
+		projectList = append(projectList, Project{
+			ID:   getString(project, "id"),
+			Name: getString(project, "name"),
+		})
+	}
+
+	return projectList, nil
+}
+
+func fetchProjectMilestones(ctx context.Context, apiKey, projectId string) ([]ProjectMilestone, error) {
+	if err := requireAPIKey(apiKey, "project milestones"); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query ProjectMilestones($projectId: String!) {
+			project(id: $projectId) {
+				projectMilestones {
+					nodes {
+						id
+						name
+					}
+				}
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"projectId": projectId})
+	if err != nil {
+		return nil, err
+	}
+
+	// A project archived or deleted since it was cached comes back null
+	if data, _ := result["data"].(map[string]interface{}); data["project"] == nil {
+		return nil, fmt.Errorf("project %s not found", projectId)
+	}
+	nodes, _, err := connectionPage(result, "project", "projectMilestones")
+	if err != nil {
+		return nil, err
+	}
+
+	milestoneList := make([]ProjectMilestone, 0, len(nodes))
+	for _, milestone := range nodes {
+		milestoneList = append(milestoneList, ProjectMilestone{
+			ID:   getString(milestone, "id"),
+			Name: getString(milestone, "name"),
+		})
+	}
+
+	return milestoneList, nil
+}
+
+// findLinearTemplate matches a Linear template by id or name.
+func findLinearTemplate(templates []LinearTemplate, value string) (*LinearTemplate, error) {
+	var names []string
+	for i, template := range templates {
+		if template.ID == value || strings.EqualFold(template.Name, value) {
+			return &templates[i], nil
+		}
+		names = append(names, template.Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no issue templates found (listing them requires LINEAR_API_KEY)")
+	}
+
+	return nil, fmt.Errorf("template not found: %s (available: %s)", value, strings.Join(names, ", "))
+}
+
+func fetchTeamSettings(ctx context.Context, apiKey, teamId string) (TeamSettings, error) {
+	if err := requireAPIKey(apiKey, "team settings"); err != nil {
+		return TeamSettings{}, err
+	}
+
+	query := `
+		query TeamSettings($teamId: String!) {
+			team(id: $teamId) {
+				id
+				name
+				key
+				issueEstimationType
+				issueEstimationAllowZero
+				defaultIssueEstimate
+				triageEnabled
+				cyclesEnabled
+				cycleDuration
+				cycleStartDay
+				defaultIssueState {
+					name
+				}
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"teamId": teamId})
+	if err != nil {
+		return TeamSettings{}, err
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	team, ok := data["team"].(map[string]interface{})
+	if !ok {
+		return TeamSettings{}, fmt.Errorf("team not found: %s", teamId)
+	}
+	settings := TeamSettings{
+		ID:                  getString(team, "id"),
+		Name:                getString(team, "name"),
+		Key:                 getString(team, "key"),
+		EstimationType:      getString(team, "issueEstimationType"),
+		EstimationAllowZero: getBool(team, "issueEstimationAllowZero"),
+		DefaultEstimate:     getFloat(team, "defaultIssueEstimate"),
+		TriageEnabled:       getBool(team, "triageEnabled"),
+		CyclesEnabled:       getBool(team, "cyclesEnabled"),
+		CycleDuration:       int(getFloat(team, "cycleDuration")),
+		CycleStartDay:       int(getFloat(team, "cycleStartDay")),
+	}
+	if state, ok := team["defaultIssueState"].(map[string]interface{}); ok {
+		settings.DefaultStateName = getString(state, "name")
+	}
+
+	return settings, nil
+}
+
+// fetchAssignableUsers lists who can be assigned or subscribed: the team's
+// members with --assignee-team-only, otherwise everyone in the organization,
+// since Linear lets anyone be assigned to any team's issues.
+func fetchAssignableUsers(ctx context.Context, apiKey, teamId string) ([]User, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		return fetchMCPTeamUsers(ctx, authHeader, teamId)
+	}
+	if teamMembersOnly {
+		return fetchTeamMembers(ctx, apiKey, teamId)
+	}
+
+	return fetchOrganizationUsers(ctx, apiKey, teamId)
+}
+
+// fetchTeamMembers lists the users who belong to the team.
+func fetchTeamMembers(ctx context.Context, apiKey, teamId string) ([]User, error) {
+	query := `
+		query TeamMembers($teamId: String!, $after: String) {
+			team(id: $teamId) {
+				members(first: 50, after: $after) {
+					nodes {
+						id
+						name
+						email
+						active
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		}
+	`
+
+	return fetchUserPages(ctx, apiKey, teamId, query, "team", "members")
+}
+
+// fetchOrganizationUsers lists every user in the team's organization,
+// whether or not they are on the team.
+func fetchOrganizationUsers(ctx context.Context, apiKey, teamId string) ([]User, error) {
+	query := `
+		query TeamUsers($teamId: String!, $after: String) {
+			team(id: $teamId) {
+				organization {
+					users(first: 50, after: $after) {
+						nodes {
+							id
+							name
+							email
+							active
+						}
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+					}
+				}
+			}
+		}
+	`
+
+	return fetchUserPages(ctx, apiKey, teamId, query, "team", "organization", "users")
+}
+
+// fetchUserPages pages through the user connection at path in the query's
+// result.
+func fetchUserPages(ctx context.Context, apiKey, teamId, query string, path ...string) ([]User, error) {
+	var userList []User
+	var after string
+
+	for {
+		variables := map[string]interface{}{"teamId": teamId}
+		if after != "" {
+			variables["after"] = after
+		}
+
+		result, err := makeLinearRequest(ctx, apiKey, query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, next, err := connectionPage(result, path...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range nodes {
+			active, ok := user["active"].(bool)
+			userList = append(userList, User{
+				ID:       getString(user, "id"),
+				Name:     getString(user, "name"),
+				Email:    getString(user, "email"),
+				Inactive: ok && !active,
+			})
+		}
+
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	return userList, nil
+}
+
+func fetchWorkflowStates(ctx context.Context, apiKey, teamId string) ([]WorkflowState, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		return fetchMCPWorkflowStates(ctx, authHeader, teamId)
+	}
+
+	var stateList []WorkflowState
+	var after string
+
+	for {
+		query := `
+			query TeamWorkflowStates($teamId: String!, $after: String) {
+				team(id: $teamId) {
+					states(first: 50, after: $after) {
+						nodes {
+							id
+							name
+							type
+						}
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{"teamId": teamId}
+		if after != "" {
+			variables["after"] = after
+		}
+
+		result, err := makeLinearRequest(ctx, apiKey, query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, next, err := connectionPage(result, "team", "states")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range nodes {
+			stateList = append(stateList, WorkflowState{
+				ID:   getString(state, "id"),
+				Name: getString(state, "name"),
+				Type: getString(state, "type"),
+			})
+		}
+
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	return stateList, nil
+}
+
+func loadTeams(ctx context.Context, apiKey string) ([]Team, error) {
+	if teams, found := loadTypedFromCache[[]Team]("teams", noCacheExpiration); found {
+		return teams, nil
+	}
+
+	teams, err := fetchTeams(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("teams", teams)
+
+	return teams, nil
+}
+
+func loadTeamLabels(ctx context.Context, apiKey, teamId string) ([]Label, error) {
+	if labels, found := loadTypedFromCache[[]Label]("labels-"+teamId, noCacheExpiration); found {
+		return labels, nil
+	}
+
+	labels, err := fetchTeamLabels(ctx, apiKey, teamId)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("labels-"+teamId, labels)
+
+	return labels, nil
+}
+
+// cacheWarmingWorkers caps concurrent requests while warming the cache so it
+// stays well under Linear's rate limits.
+const cacheWarmingWorkers = 3
+
+// warmTeamCaches fills the cache with every team's labels, users, and
+// workflow states except skipTeamId's, which the form already loaded.
+// Already cached teams cost nothing, and errors are ignored: the data is
+// fetched again when the team is picked.
+func warmTeamCaches(ctx context.Context, apiKey string, teams []Team, skipTeamId string) {
+	teamIds := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < cacheWarmingWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for teamId := range teamIds {
+				warmTeamCache(ctx, apiKey, teamId)
+			}
+		}()
+	}
+
+	for _, team := range teams {
+		if team.ID != skipTeamId {
+			teamIds <- team.ID
+		}
+	}
+	close(teamIds)
+	wg.Wait()
+}
+
+// warmTeamCache loads one team's data into the cache. Each loader reports
+// its own errors, so a failure in one still lets the others run.
+func warmTeamCache(ctx context.Context, apiKey, teamId string) {
+	loadTeamLabels(ctx, apiKey, teamId)
+	loadTeamUsers(ctx, apiKey, teamId)
+	loadWorkflowStates(ctx, apiKey, teamId)
+}
+
+func loadTeamUsers(ctx context.Context, apiKey, teamId string) ([]User, error) {
+	// Team members are cached apart from the whole organization's users
+	cacheKey := "users-" + teamId
+	if teamMembersOnly {
+		cacheKey = "members-" + teamId
+	}
+	if users, found := loadTypedFromCache[[]User](cacheKey, noCacheExpiration); found {
+		return users, nil
+	}
+
+	users, err := fetchAssignableUsers(ctx, apiKey, teamId)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache(cacheKey, users)
+
+	return users, nil
+}
+
+func loadWorkflowStates(ctx context.Context, apiKey, teamId string) ([]WorkflowState, error) {
+	if states, found := loadTypedFromCache[[]WorkflowState]("states-"+teamId, noCacheExpiration); found {
+		return states, nil
+	}
+
+	states, err := fetchWorkflowStates(ctx, apiKey, teamId)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("states-"+teamId, states)
+
+	return states, nil
+}
+
+func loadTeamProjects(ctx context.Context, apiKey, teamId string) ([]Project, error) {
+	if projects, found := loadTypedFromCache[[]Project]("projects-"+teamId, teamSettingsCacheTTL); found {
+		return projects, nil
+	}
+
+	projects, err := fetchTeamProjects(ctx, apiKey, teamId)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("projects-"+teamId, projects)
+
+	return projects, nil
+}
+
+func loadProjectMilestones(ctx context.Context, apiKey, projectId string) ([]ProjectMilestone, error) {
+	if milestones, found := loadTypedFromCache[[]ProjectMilestone]("milestones-"+projectId, teamSettingsCacheTTL); found {
+		return milestones, nil
+	}
+
+	milestones, err := fetchProjectMilestones(ctx, apiKey, projectId)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("milestones-"+projectId, milestones)
+
+	return milestones, nil
+}
+
+func loadTeamTemplates(ctx context.Context, apiKey, teamId string) ([]LinearTemplate, error) {
+	if templates, found := loadTypedFromCache[[]LinearTemplate]("templates-"+teamId, teamSettingsCacheTTL); found {
+		return templates, nil
+	}
+
+	templates, err := fetchTemplates(ctx, apiKey, teamId)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("templates-"+teamId, templates)
+
+	return templates, nil
+}
+
+func loadTeamSettings(ctx context.Context, apiKey, teamId string) (TeamSettings, error) {
+	if settings, found := loadTypedFromCache[TeamSettings]("settings-"+teamId, teamSettingsCacheTTL); found {
+		return settings, nil
+	}
+
+	settings, err := fetchTeamSettings(ctx, apiKey, teamId)
+	if err != nil {
+		return TeamSettings{}, err
+	}
+	saveToCache("settings-"+teamId, settings)
+
+	return settings, nil
+}
+
+// estimateFromOptions validates --estimate against the team's estimate scale
+// and exits listing the allowed values when it doesn't fit.
+func estimateFromOptions(ctx context.Context, apiKey, teamId string, options CreateOptions) string {
+	estimateType := options.EstimateType
+	if !options.EstimateTypeSet {
+		estimateType = detectEstimateType(ctx, apiKey, teamId, options.EstimateType)
+	}
+
+	if options.Estimate == autoEstimate {
+		return teamDefaultEstimate(ctx, apiKey, teamId, estimateType)
+	}
+
+	estimate, err := resolveEstimate(estimateType, options.Estimate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid estimate for this team: %v\n", err)
+		os.Exit(1)
+	}
+
+	return estimate
+}
+
+// teamDefaultEstimate returns the team's configured default estimate, or ""
+// when there is none or it isn't on the estimateType scale.
+func teamDefaultEstimate(ctx context.Context, apiKey, teamId string, estimateType int) string {
+	if _, ok := splitMCPAuthHeader(apiKey); ok {
+		return ""
+	}
+
+	settings, err := loadTeamSettings(ctx, apiKey, teamId)
+	if err != nil || settings.DefaultEstimate == 0 {
+		return ""
+	}
+
+	estimate := strconv.FormatFloat(settings.DefaultEstimate, 'f', -1, 64)
+	if !hasOptionValue(getEstimateOptions(estimateType), estimate) {
+		return ""
+	}
+
+	return estimate
+}
+
+// detectEstimateType returns the team's estimate scale, or fallback when it can't be determined.
+func detectEstimateType(ctx context.Context, apiKey, teamId string, fallback int) int {
+	if _, ok := splitMCPAuthHeader(apiKey); ok {
+		return fallback
+	}
+
+	settings, err := loadTeamSettings(ctx, apiKey, teamId)
+	if err != nil || settings.EstimationType == "" {
+		return fallback
+	}
+
+	return estimateTypeFromTeam(settings.EstimationType)
+}
+
+// searchIssues runs a full-text issue search, optionally scoped to a team.
+func searchIssues(ctx context.Context, apiKey, term, teamId string, limit int) ([]Issue, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		arguments := map[string]interface{}{"query": term, "limit": limit}
+		if teamId != "" {
+			arguments["team"] = teamId
+		}
+
+		data, err := callMCPTool(ctx, authHeader, "list_issues", arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		var page MCPPage[MCPIssue]
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		issues := make([]Issue, 0, len(page.Issues))
+		for _, issue := range page.Issues {
+			issues = append(issues, Issue{
+				Identifier: issue.ID,
+				BranchName: issue.GitBranchName,
+				Title:      issue.Title,
+				URL:        issue.URL,
+			})
+		}
+		if len(issues) > limit {
+			issues = issues[:limit]
+		}
+
+		return issues, nil
+	}
+
+	query := `
+		query SearchIssues($term: String!, $first: Int, $teamId: String) {
+			searchIssues(term: $term, first: $first, teamId: $teamId) {
+				nodes {
+					identifier
+					branchName
+					title
+					url
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{"term": term, "first": limit}
+	if teamId != "" {
+		variables["teamId"] = teamId
+	}
+	result, err := makeLinearRequest(ctx, apiKey, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := connectionPage(result, "searchIssues")
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(nodes))
+	for _, issue := range nodes {
+		issues = append(issues, Issue{
+			Identifier: getString(issue, "identifier"),
+			BranchName: getString(issue, "branchName"),
+			Title:      getString(issue, "title"),
+			URL:        getString(issue, "url"),
+		})
+	}
+
+	return issues, nil
+}
+
+func fetchTeamIssues(ctx context.Context, apiKey, teamId string) ([]Issue, error) {
+	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
+		return fetchMCPTeamIssues(ctx, authHeader, teamId)
+	}
+
+	var issues []Issue
+	var after string
+
+	for len(issues) < 250 {
+		query := `
+			query TeamIssues($teamId: String!, $after: String) {
+				team(id: $teamId) {
+					issues(first: 50, after: $after, orderBy: updatedAt) {
+						nodes {
+							identifier
+							title
+							branchName
+							url
+						}
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{"teamId": teamId}
+		if after != "" {
+			variables["after"] = after
+		}
+
+		result, err := makeLinearRequest(ctx, apiKey, query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, next, err := connectionPage(result, "team", "issues")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range nodes {
+			issues = append(issues, Issue{
+				Identifier: getString(issue, "identifier"),
+				Title:      getString(issue, "title"),
+				BranchName: getString(issue, "branchName"),
+				URL:        getString(issue, "url"),
+			})
+		}
+
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	return issues, nil
+}
+
+// Estimate scales, as used by getEstimateOptions and --estimate-type.
+const (
+	estimateTypeNone = iota
+	estimateTypeTShirt
+	estimateTypeFibonacci
+	estimateTypeStoryPoints
+	estimateTypeLinear
+	estimateTypeExponential
+)
+
+func getEstimateOptions(estimateType int) []huh.Option[string] {
+	switch estimateType {
+	case estimateTypeNone:
+		return []huh.Option[string]{
+			{Key: "No estimate", Value: "0"},
+		}
+	case estimateTypeTShirt:
+		return []huh.Option[string]{
+			{Key: "XS - Extra Small", Value: "1"},
+			{Key: "S - Small", Value: "2"},
+			{Key: "M - Medium", Value: "3"},
+			{Key: "L - Large", Value: "5"},
+			{Key: "XL - Extra Large", Value: "8"},
+		}
+	case estimateTypeFibonacci:
+		return []huh.Option[string]{
+			{Key: "1", Value: "1"},
+			{Key: "2", Value: "2"},
+			{Key: "3", Value: "3"},
+			{Key: "5", Value: "5"},
+			{Key: "8", Value: "8"},
+			{Key: "13", Value: "13"},
+			{Key: "21", Value: "21"},
+		}
+	case estimateTypeLinear:
+		return []huh.Option[string]{
+			{Key: "1", Value: "1"},
+			{Key: "2", Value: "2"},
+			{Key: "3", Value: "3"},
+			{Key: "4", Value: "4"},
+			{Key: "5", Value: "5"},
+			{Key: "6", Value: "6"},
+			{Key: "7", Value: "7"},
+		}
+	case estimateTypeExponential:
+		return []huh.Option[string]{
+			{Key: "1", Value: "1"},
+			{Key: "2", Value: "2"},
+			{Key: "4", Value: "4"},
+			{Key: "8", Value: "8"},
+			{Key: "16", Value: "16"},
+			{Key: "32", Value: "32"},
+			{Key: "64", Value: "64"},
+		}
+	default: // estimateTypeStoryPoints, Linear's default
+		return []huh.Option[string]{
+			{Key: "0 - No estimate", Value: "0"},
+			{Key: "1 - Small (< 1 day)", Value: "1"},
+			{Key: "2 - Medium (1-2 days)", Value: "2"},
+			{Key: "3 - Large (3-5 days)", Value: "3"},
+			{Key: "5 - Extra Large (1+ weeks)", Value: "5"},
+			{Key: "8 - Epic (2+ weeks)", Value: "8"},
+		}
+	}
+}
+
+// estimatePickerOptions is getEstimateOptions with the configured hour
+// equivalents added to the keys, for display only.
+func estimatePickerOptions(estimateType int) []huh.Option[string] {
+	options := getEstimateOptions(estimateType)
+	hoursPerPoint := loadConfig().HoursPerPoint
+	for i := range options {
+		if hours := estimateHours(options[i].Value, hoursPerPoint); hours != "" {
+			options[i].Key += " (" + hours + ")"
+		}
+	}
+
+	return options
+}
+
+// estimateHours renders estimate points as hours, e.g. "~12h". It is empty
+// when hoursPerPoint is off or there is no estimate.
+func estimateHours(estimate string, hoursPerPoint float64) string {
+	points, err := strconv.ParseFloat(estimate, 64)
+	if hoursPerPoint <= 0 || err != nil || points <= 0 {
+		return ""
+	}
+
+	return "~" + strconv.FormatFloat(points*hoursPerPoint, 'f', -1, 64) + "h"
+}
+
+func getPriorityOptions() []huh.Option[string] {
+	return []huh.Option[string]{
+		{Key: "No priority", Value: "0"},
+		{Key: "Urgent", Value: "1"},
+		{Key: "High", Value: "2"},
+		{Key: "Medium", Value: "3"},
+		{Key: "Low", Value: "4"},
+	}
+}
+
+// priorityLabel names a priority value with a signal-bar icon like Linear's,
+// e.g. "▮▮▯ Medium". Unset priorities read "No priority" without an icon.
+func priorityLabel(priority string) string {
+	if priority == "" || priority == "0" {
+		return "No priority"
+	}
+
+	bar, noBar, urgent := glyphBar, glyphNoBar, glyphUrgent
+	if plainOutput {
+		bar, noBar, urgent = "#", "-", "!!"
+	}
+	icons := map[string]string{
+		"1": urgent,
+		"2": strings.Repeat(bar, 3),
+		"3": strings.Repeat(bar, 2) + noBar,
+		"4": bar + strings.Repeat(noBar, 2),
+	}
+	for _, option := range getPriorityOptions() {
+		if option.Value == priority {
+			return icons[priority] + " " + option.Key
+		}
+	}
+
+	return priority
+}
+
+// estimateTypeFromTeam maps Linear's issueEstimationType to a getEstimateOptions style.
+func estimateTypeFromTeam(estimationType string) int {
+	switch estimationType {
+	case "notUsed":
+		return estimateTypeNone
+	case "tShirt":
+		return estimateTypeTShirt
+	case "fibonacci":
+		return estimateTypeFibonacci
+	case "linear":
+		return estimateTypeLinear
+	case "exponential":
+		return estimateTypeExponential
+	default:
+		return estimateTypeStoryPoints
+	}
+}
+
+// parseEstimateType maps an --estimate-type value to an estimate scale.
+// "linear" is Linear's linear scale (1-7), and "points" the story points
+// scale Linear uses by default.
+func parseEstimateType(value string) (int, error) {
+	switch strings.ToLower(value) {
+	case "none":
+		return estimateTypeNone, nil
+	case "tshirt":
+		return estimateTypeTShirt, nil
+	case "fibonacci":
+		return estimateTypeFibonacci, nil
+	case "points":
+		return estimateTypeStoryPoints, nil
+	case "linear":
+		return estimateTypeLinear, nil
+	case "exponential":
+		return estimateTypeExponential, nil
+	default:
+		return 0, fmt.Errorf("unknown estimate type %q (expected none, tshirt, fibonacci, points, linear, or exponential)", value)
+	}
+}
+
+func estimateTypeName(estimateType int) string {
+	switch estimateType {
+	case estimateTypeNone:
+		return "No estimates"
+	case estimateTypeTShirt:
+		return "T-shirt sizes"
+	case estimateTypeFibonacci:
+		return "Fibonacci"
+	case estimateTypeLinear:
+		return "Linear"
+	case estimateTypeExponential:
+		return "Exponential"
+	default:
+		return "Story points"
+	}
+}
+
+// estimateLabel names estimate on the team's estimateType scale, e.g. "M - Medium"
+// for 3 on t-shirt sizes. Values off the scale (such as --estimate-raw) are shown as is.
+func estimateLabel(estimateType int, estimate string) string {
+	if estimate == "" || estimate == "0" {
+		return "No estimate"
+	}
+	for _, option := range getEstimateOptions(estimateType) {
+		if option.Value == estimate {
+			return option.Key
+		}
+	}
+
+	return estimate
+}
+
+func hasOptionValue(options []huh.Option[string], value string) bool {
+	for _, option := range options {
+		if option.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordUsage counts a pick of key, creating usage if needed.
+func recordUsage(usage map[string]Usage, key string) map[string]Usage {
+	if usage == nil {
+		usage = make(map[string]Usage)
+	}
+	entry := usage[key]
+	entry.Count++
+	entry.LastUsed = time.Now()
+	usage[key] = entry
+
+	return usage
+}
+
+// usageScore weighs how often something was picked by how recently, halving
+// a pick's weight after a week, so old habits fade.
+func usageScore(usage Usage, now time.Time) float64 {
+	days := now.Sub(usage.LastUsed).Hours() / 24
+	return float64(usage.Count) / (1 + days/7)
+}
+
+// sortOptionsByUsage moves the most used options to the top; options never
+// picked keep their order after them.
+func sortOptionsByUsage(options []huh.Option[string], usage map[string]Usage) {
+	if len(usage) == 0 {
+		return
+	}
+
+	now := time.Now()
+	sort.SliceStable(options, func(i, j int) bool {
+		return usageScore(usage[options[i].Value], now) > usageScore(usage[options[j].Value], now)
+	})
+}
+
+func teamOptions(teams []Team) []huh.Option[string] {
+	options := make([]huh.Option[string], len(teams))
+	for i, team := range teams {
+		options[i] = huh.Option[string]{Key: team.Name, Value: team.ID}
+	}
+
+	return options
+}
+
+// labelOptionKey prefixes the label name with its group and a swatch in its
+// Linear color, unless colors are off (NO_COLOR or --plain).
+func labelOptionKey(label Label) string {
+	name := label.Name
+	if label.GroupName != "" {
+		name = label.GroupName + " / " + label.Name
+	}
+	if label.Color == "" || plainOutput || os.Getenv("NO_COLOR") != "" {
+		return name
+	}
+
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(label.Color)).Render(glyphSwatch) + " " + name
+}
+
+// labelOptions lists ungrouped labels first, then each group's children
+// together. Group labels themselves can't be applied, so they're left out.
+func labelOptions(labels []Label) ([]huh.Option[string], map[string]string) {
+	selectable := make([]Label, 0, len(labels))
+	for _, label := range labels {
+		if !label.IsGroup {
+			selectable = append(selectable, label)
+		}
+	}
+	sort.SliceStable(selectable, func(i, j int) bool {
+		return selectable[i].GroupName < selectable[j].GroupName
+	})
+
+	options := make([]huh.Option[string], len(selectable))
+	labelMap := make(map[string]string)
+	for i, label := range selectable {
+		options[i] = huh.Option[string]{Key: labelOptionKey(label), Value: label.Name}
+		labelMap[label.Name] = label.ID
+	}
+
+	return options, labelMap
+}
+
+// labelGroupPickerThreshold is the number of selectable labels above which
+// the form asks for a label group before showing the multiselect.
+const labelGroupPickerThreshold = 30
+
+const (
+	allLabelGroups  = ""
+	ungroupedLabels = "-"
+)
+
+// labelGroupOptions lists "All", each label group, and "Ungrouped" when some
+// labels don't belong to a group. It returns nil when the team has no groups.
+func labelGroupOptions(labels []Label) []huh.Option[string] {
+	var groups []string
+	seen := make(map[string]bool)
+	hasUngrouped := false
+	for _, label := range labels {
+		if label.IsGroup {
+			continue
+		}
+		if label.GroupName == "" {
+			hasUngrouped = true
+			continue
+		}
+		if !seen[label.GroupName] {
+			seen[label.GroupName] = true
+			groups = append(groups, label.GroupName)
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	sort.Strings(groups)
+
+	options := []huh.Option[string]{huh.NewOption("All", allLabelGroups)}
+	for _, group := range groups {
+		options = append(options, huh.NewOption(group, group))
+	}
+	if hasUngrouped {
+		options = append(options, huh.NewOption("Ungrouped", ungroupedLabels))
+	}
+
+	return options
+}
+
+// labelsInGroup narrows options to the labels in group. Labels that are
+// already selected stay in the list so switching groups doesn't drop them.
+func labelsInGroup(options []huh.Option[string], labels []Label, group string, selected []string) []huh.Option[string] {
+	if group == allLabelGroups {
+		return options
+	}
+
+	groupByName := make(map[string]string, len(labels))
+	for _, label := range labels {
+		groupByName[label.Name] = label.GroupName
+	}
+	if group == ungroupedLabels {
+		group = ""
+	}
+
+	filtered := make([]huh.Option[string], 0, len(options))
+	for _, option := range options {
+		if groupByName[option.Value] == group || slices.Contains(selected, option.Value) {
+			filtered = append(filtered, option)
+		}
+	}
+
+	return filtered
+}
+
+func exclusiveLabelConflict(selected []string, labels []Label) error {
+	labelByName := make(map[string]Label, len(labels))
+	for _, label := range labels {
+		labelByName[label.Name] = label
+	}
+
+	selectedByGroup := make(map[string]string)
+	for _, name := range selected {
+		label, ok := labelByName[name]
+		if !ok || label.GroupID == "" {
+			continue
+		}
+		if other, exists := selectedByGroup[label.GroupID]; exists {
+			return fmt.Errorf("only one %s label can be selected (%s, %s)", label.GroupName, other, name)
+		}
+		selectedByGroup[label.GroupID] = name
+	}
+
+	return nil
+}
+
+func enforceExclusiveLabels(selected []string, labels []Label) []string {
+	groupByName := make(map[string]string, len(labels))
+	for _, label := range labels {
+		groupByName[label.Name] = label.GroupID
+	}
+
+	lastInGroup := make(map[string]string)
+	for _, name := range selected {
+		if groupID := groupByName[name]; groupID != "" {
+			lastInGroup[groupID] = name
+		}
+	}
+
+	var result []string
+	for _, name := range selected {
+		groupID := groupByName[name]
+		if groupID != "" && lastInGroup[groupID] != name {
+			continue
+		}
+		result = append(result, name)
+	}
+
+	return result
+}
+
+func activeUsers(users []User) []User {
+	var active []User
+	for _, user := range users {
+		if !user.Inactive {
+			active = append(active, user)
+		}
+	}
+
+	return active
+}
+
+func subscriberOptions(users []User) []huh.Option[string] {
+	options := make([]huh.Option[string], len(users))
+	for i, user := range users {
+		options[i] = huh.Option[string]{Key: user.Name, Value: user.ID}
+	}
+
+	return options
+}
+
+func userNames(users []User, userIds []string) []string {
+	nameById := make(map[string]string, len(users))
+	for _, user := range users {
+		nameById[user.ID] = user.Name
+	}
+
+	names := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		if name, ok := nameById[userId]; ok {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func findTeam(teams []Team, teamId string) *Team {
+	for _, team := range teams {
+		if team.ID == teamId {
+			return &team
+		}
+	}
+
+	return nil
+}
+
+func requireDefaultTeam(selections UserSelections) string {
+	if selections.TeamId == "" {
+		fmt.Fprintln(os.Stderr, errorSymbol+" No default team set")
+		fmt.Fprintln(os.Stderr, "Run `lnr set-team` first")
+		os.Exit(1)
+	}
+
+	return selections.TeamId
+}
+
+// matchTeam finds a team by id, key (e.g. "ENG"), or name, ignoring case.
+func matchTeam(teams []Team, value string) *Team {
+	for _, team := range teams {
+		if team.ID == value || (team.Key != "" && strings.EqualFold(team.Key, value)) {
+			return &team
+		}
+	}
+	for _, team := range teams {
+		if strings.EqualFold(team.Name, value) {
+			return &team
+		}
+	}
+
+	return nil
+}
+
+func resolveTeam(ctx context.Context, apiKey, value string) (*Team, error) {
+	teams, err := loadTeams(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if team := matchTeam(teams, value); team != nil {
+		return team, nil
+	}
+
+	// Cached teams may predate the key field or a newly created team
+	teams, err = fetchTeams(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	saveToCache("teams", teams)
+	if team := matchTeam(teams, value); team != nil {
+		return team, nil
+	}
+
+	return nil, fmt.Errorf("team not found: %s", value)
+}
+
+func enabledText(enabled bool) string {
+	if enabled {
+		return "Enabled"
+	}
+
+	return "Disabled"
+}
+
+func runDescribeTeam(ctx context.Context, apiKey, teamName string, jsonOutput bool) {
+	teamId := ""
+	if teamName == "" {
+		teamId = requireDefaultTeam(loadUserSelections())
+	} else {
+		team, err := resolveTeam(ctx, apiKey, teamName)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error resolving team: %v\n", err)
+			os.Exit(1)
+		}
+		teamId = team.ID
+	}
+
+	settings, err := fetchTeamSettings(ctx, apiKey, teamId)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching team settings: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		jsonData, err := json.Marshal(settings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	cycles := enabledText(settings.CyclesEnabled)
+	if settings.CyclesEnabled {
+		cycles = fmt.Sprintf("Enabled, %d week(s), starting %s", settings.CycleDuration, time.Weekday(settings.CycleStartDay%7))
+	}
+	defaultState := settings.DefaultStateName
+	if defaultState == "" {
+		defaultState = "None"
+	}
+
+	fmt.Printf("Team:           %s (%s)\n", settings.Name, settings.Key)
+	fmt.Printf("Estimates:      %s (%s)\n", estimateTypeName(estimateTypeFromTeam(settings.EstimationType)), settings.EstimationType)
+	fmt.Printf("Triage:         %s\n", enabledText(settings.TriageEnabled))
+	fmt.Printf("Cycles:         %s\n", cycles)
+	fmt.Printf("Default status: %s\n", defaultState)
+}
+
+func runSetTeam(ctx context.Context, apiKey string) {
+	requireTerminal("set-team needs an interactive terminal")
+	teams, err := loadTeams(ctx, apiKey)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching teams: %v\n", err)
+		os.Exit(1)
+	}
+
+	selections := loadUserSelections()
+	selectedTeamId := selections.TeamId
+	options := teamOptions(teams)
+	sortOptionsByUsage(options, selections.TeamUsage)
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Default Team").
+				Description("Filter and select the team to use for quick actions").
+				Options(options...).
+				Filtering(true).
+				Value(&selectedTeamId),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Team selection")
+	}
+
+	selections.switchTeam(selectedTeamId)
+	if err := saveUserSelections(selections); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error saving default team: %v\n", err)
+		os.Exit(1)
+	}
+
+	selectedTeam := findTeam(teams, selectedTeamId)
+	if selectedTeam != nil {
+		fmt.Printf(successSymbol+" Default team set to %s\n", selectedTeam.Name)
+		return
+	}
+	fmt.Println(successSymbol + " Default team saved")
+}
+
+func runSetLabels(ctx context.Context, apiKey string) {
+	requireTerminal("set-labels needs an interactive terminal")
+	selections := loadUserSelections()
+	teamId := requireDefaultTeam(selections)
+
+	labels, err := loadTeamLabels(ctx, apiKey, teamId)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching labels: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(labels) == 0 {
+		fmt.Fprintln(os.Stderr, infoSymbol+" No labels configured for the default team")
+		return
+	}
+
+	selectedLabels := enforceExclusiveLabels(selections.Labels, labels)
+	options, _ := labelOptions(labels)
+	sortOptionsByUsage(options, selections.LabelUsage)
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Default Labels").
+				Description("Filter and select labels to apply in quick mode").
+				Options(options...).
+				Filtering(true).
+				Value(&selectedLabels).
+				Validate(func(selected []string) error {
+					return exclusiveLabelConflict(selected, labels)
+				}).
+				Limit(4),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Label selection")
+	}
+
+	selections.Labels = selectedLabels
+	if err := saveUserSelections(selections); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error saving default labels: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(selectedLabels) == 0 {
+		fmt.Println(successSymbol + " Default labels cleared")
+		return
+	}
+	fmt.Printf(successSymbol+" Default labels set to %s\n", strings.Join(selectedLabels, ", "))
+}
+
+func runSetEstimate(estimateType int) {
+	requireTerminal("set-estimate needs an interactive terminal")
+	selections := loadUserSelections()
+	selectedEstimate := selections.Estimate
+	estimateOptions := estimatePickerOptions(estimateType)
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Default Estimate").
+				Description("Select the estimate to apply in quick mode").
+				Options(estimateOptions...).
+				Value(&selectedEstimate),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Estimate selection")
+	}
+
+	selections.Estimate = selectedEstimate
+	if err := saveUserSelections(selections); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error saving default estimate: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, option := range estimateOptions {
+		if option.Value == selectedEstimate {
+			fmt.Printf(successSymbol+" Default estimate set to %s\n", option.Key)
+			return
+		}
+	}
+	fmt.Println(successSymbol + " Default estimate saved")
+}
+
+func runSetStatus(ctx context.Context, apiKey string) {
+	requireTerminal("set-status needs an interactive terminal")
+	selections := loadUserSelections()
+	teamId := requireDefaultTeam(selections)
+
+	workflowStates, err := loadWorkflowStates(ctx, apiKey, teamId)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching workflow states: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(workflowStates) == 0 {
+		fmt.Fprintln(os.Stderr, infoSymbol+" No workflow states configured for the default team")
+		return
+	}
+
+	statusOptions := make([]huh.Option[string], len(workflowStates)+1)
+	statusOptions[0] = huh.Option[string]{Key: "No default status", Value: ""}
+	for i, state := range workflowStates {
+		statusOptions[i+1] = huh.Option[string]{Key: state.Name, Value: state.ID}
+	}
+
+	selectedStatusId := selections.StatusId
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Default Status").
+				Description("Select the status to apply to new issues").
+				Options(statusOptions...).
+				Filtering(true).
+				Value(&selectedStatusId),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Status selection")
+	}
+
+	selections.StatusId = selectedStatusId
+	if err := saveUserSelections(selections); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error saving default status: %v\n", err)
+		os.Exit(1)
+	}
+
+	if selectedStatusId == "" {
+		fmt.Println(successSymbol + " Default status cleared")
+		return
+	}
+
+	for _, state := range workflowStates {
+		if state.ID == selectedStatusId {
+			fmt.Printf(successSymbol+" Default status set to %s\n", state.Name)
+			return
+		}
+	}
+	fmt.Println(successSymbol + " Default status saved")
+}
+
+func runQuickCreate(ctx context.Context, apiKey, title string, options CreateOptions) {
+	if strings.TrimSpace(title) == "" {
+		title = options.Title
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		fmt.Fprintln(os.Stderr, errorSymbol+" Title cannot be empty")
+		os.Exit(1)
+	}
+	title, err := fitTitle(title, options.TruncateTitle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+		os.Exit(1)
+	}
+	resolveParentAuto(ctx, apiKey, &options)
+
+	selections := loadUserSelections()
+	if options.TeamName == "" {
+		if teams, err := loadTeams(ctx, apiKey); err == nil {
+			if team := repoDefaultTeam(teams, selections); team != nil {
+				selections.switchTeam(team.ID)
+			}
+		}
+	}
+	teamId := ""
+	if options.TeamName != "" {
+		team, err := resolveTeam(ctx, apiKey, options.TeamName)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error resolving team: %v\n", err)
+			os.Exit(1)
+		}
+		teamId = team.ID
+	} else if selections.TeamId == "" && isatty.IsTerminal(os.Stdin.Fd()) {
+		// Ask once and keep the answer as the default team
+		teams, err := loadTeams(ctx, apiKey)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching teams: %v\n", err)
+			os.Exit(1)
+		}
+		selections.switchTeam(selectTeam(teams, "", selections.TeamUsage).ID)
+		if err := saveUserSelections(selections); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error saving default team: %v\n", err)
+		}
+		teamId = selections.TeamId
+	} else {
+		teamId = requireDefaultTeam(selections)
+	}
+	labels, err := loadTeamLabels(ctx, apiKey, teamId)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching labels: %v\n", err)
+		os.Exit(1)
+	}
+	if options.CreateMissingLabels {
+		if labels, err = createMissingLabels(ctx, apiKey, teamId, labels, options.Labels); err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+	}
+	_, labelMap := labelOptions(labels)
+
+	// Use the choices last made for this team, not the default team's
+	teamSelections := selections.forTeam(teamId)
+	if len(options.Labels) > 0 {
+		resolved, err := optionLabels(options.Labels, labelMap, "this team")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		teamSelections.Labels = resolved
+	}
+	teamDefault := ""
+	var teamSubscribers []string
+	if config := loadConfig(); len(config.DefaultAssignees) > 0 || len(config.DefaultSubscribers) > 0 {
+		if teams, err := loadTeams(ctx, apiKey); err == nil {
+			if team := findTeam(teams, teamId); team != nil {
+				teamDefault = config.defaultAssignee(*team)
+				teamSubscribers = config.defaultSubscribers(*team)
+			}
+		}
+	}
+	if _, ok := splitMCPAuthHeader(apiKey); ok {
+		warnSkippedDefaultSubscribers(teamSubscribers, options)
+		teamSubscribers = nil
+		teamSelections.SubscriberIds = nil
+	}
+	needsDefaultSubscribers := len(teamSelections.SubscriberIds) == 0 && len(teamSubscribers) > 0 && !options.NoSubscribers
+	var users []User
+	if options.Assignee != "" || (teamSelections.AssigneeId == "" && teamDefault != "" && !options.Unassigned) || needsDefaultSubscribers {
+		users, err = loadTeamUsers(ctx, apiKey, teamId)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching users: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	teamSelections.AssigneeId, err = resolveAssignee(users, teamSelections.AssigneeId, teamDefault, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid assignee: %v\n", err)
+		os.Exit(1)
+	}
+	teamSelections.SubscriberIds, err = resolveSubscribers(users, teamSelections.SubscriberIds, teamSubscribers, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid subscribers: %v\n", err)
+		os.Exit(1)
+	}
+	if options.StatusType != "" {
+		states, err := loadWorkflowStates(ctx, apiKey, teamId)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching workflow states: %v\n", err)
+			os.Exit(1)
+		}
+		statusId, err := statusFromType(states, options.StatusType, "this team")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		teamSelections.StatusId = statusId
+	}
+	estimate := teamSelections.Estimate
+	if options.EstimateRaw != "" {
+		estimate = options.EstimateRaw
+	} else if options.Estimate != "" {
+		estimate = estimateFromOptions(ctx, apiKey, teamId, options)
+	} else if estimateType := detectEstimateType(ctx, apiKey, teamId, -1); estimateType >= 0 {
+		if !hasOptionValue(getEstimateOptions(estimateType), estimate) {
+			estimate = ""
+		}
+		if estimate == "" {
+			estimate = teamDefaultEstimate(ctx, apiKey, teamId, estimateType)
+		}
+	}
+
+	description := options.Description
+	if options.DescriptionTemplate != "" {
+		description = options.DescriptionTemplate
+	}
+
+	ticket := LinearTicket{
+		Title:         title,
+		Description:   description,
+		TeamId:        teamId,
+		Labels:        enforceExclusiveLabels(teamSelections.Labels, labels),
+		Estimate:      estimate,
+		AssigneeId:    teamSelections.AssigneeId,
+		SubscriberIds: teamSelections.SubscriberIds,
+		StatusId:      teamSelections.StatusId,
+		Fields:        options.Fields,
+		Position:      options.Position,
+		Images:        options.Images,
+		ParentId:      options.ParentId,
+	}
+	if options.TemplateID != "" {
+		templates, err := loadTeamTemplates(ctx, apiKey, teamId)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching templates: %v\n", err)
+			os.Exit(1)
+		}
+		template, err := findLinearTemplate(templates, options.TemplateID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --template-id: %v\n", err)
+			os.Exit(1)
+		}
+		ticket.TemplateId = template.ID
+	}
+	applyIdempotencyKey(apiKey, &ticket, options)
+	if offlineMode {
+		queueTicketOrExit(ticket, labelMap, options)
+		return
+	}
+
+	issue, err := createLinearTicket(ctx, apiKey, ticket, labelMap)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error creating ticket: %v\n", err)
+		os.Exit(1)
+	}
+	recordHistory(issue, teamId)
+	writeOutputFile(options, issue)
+	attachLinkFromOptions(ctx, apiKey, issue, options)
+	relateIssuesFromOptions(ctx, apiKey, issue, options)
+
+	if options.Open {
+		openIssue(issue)
+	}
+
+	branchName := fallbackBranchName(issue)
+	issue.BranchName = branchName
+	if !options.JSONOutput {
+		switch {
+		case options.CopyBranch:
+			copyToClipboard("Branch name", branchName)
+		case options.Slack:
+			copyToClipboard("Slack message", slackMessage(issue))
+		}
+		if quietOutput {
+			fmt.Println(issue.Identifier)
+			return
+		}
+		if options.CopyBranch || options.Slack {
+			return
+		}
+	}
+	if options.JSONOutput {
+		jsonData, err := json.Marshal(issue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	if err := clipboard.WriteAll(branchName); err != nil {
+		fmt.Println(branchName)
+		fmt.Fprintln(os.Stderr, errorSymbol+" "+clipboardErrorMessage(err))
+		return
+	}
+
+	fmt.Println(branchName)
+}
+
+func parseImportFile(path string) ([]ImportTicket, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return parseImportCSV(data)
+	}
+
+	var records []json.RawMessage
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	// Check every record against ImportTicket so a typo such as "titel" is
+	// reported with its record number instead of silently dropped
+	tickets := make([]ImportTicket, 0, len(records))
+	var errs []error
+	for i, record := range records {
+		decoder := json.NewDecoder(bytes.NewReader(record))
+		decoder.DisallowUnknownFields()
+		var ticket ImportTicket
+		if err := decoder.Decode(&ticket); err != nil {
+			errs = append(errs, fmt.Errorf("record #%d: %w", i+1, err))
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return tickets, nil
+}
+
+// parseImportCSV reads tickets from a CSV with a header row. Columns are
+// matched by name; labels are comma-separated within their cell.
+func parseImportCSV(data []byte) ([]ImportTicket, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("CSV is missing a title column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	tickets := make([]ImportTicket, 0, len(records)-1)
+	for _, record := range records[1:] {
+		ticket := ImportTicket{
+			Title:       field(record, "title"),
+			Description: field(record, "description"),
+			Assignee:    field(record, "assignee"),
+			Estimate:    importEstimate(field(record, "estimate")),
+			Team:        field(record, "team"),
+		}
+		for _, label := range strings.Split(field(record, "labels"), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				ticket.Labels = append(ticket.Labels, label)
+			}
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, nil
+}
+
+// DescriptionFile is a markdown ticket draft read with --description-file.
+type DescriptionFile struct {
+	Title       string
+	Labels      []string
+	Estimate    string
+	Assignee    string
+	Team        string
+	Description string
+}
+
+// parseDescriptionFile splits optional front-matter from the markdown body.
+// Front-matter supports simple "key: value" lines; labels may be an inline
+// list ("[Bug, Backend]") or a block of "- item" lines. Unknown keys are
+// ignored so drafts can carry their own metadata.
+func parseDescriptionFile(content string) (DescriptionFile, error) {
+	var file DescriptionFile
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		file.Description = strings.TrimSpace(content)
+		return file, nil
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return file, fmt.Errorf("front-matter is missing its closing ---")
+	}
+
+	inLabels := false
+	for i, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if inLabels && strings.HasPrefix(trimmed, "- ") {
+			if label := unquoteFrontMatter(trimmed[2:]); label != "" {
+				file.Labels = append(file.Labels, label)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return file, fmt.Errorf("front-matter line %d: expected key: value", i+2)
+		}
+		value = strings.TrimSpace(value)
+		inLabels = false
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			file.Title = unquoteFrontMatter(value)
+		case "estimate":
+			file.Estimate = unquoteFrontMatter(value)
+		case "assignee":
+			file.Assignee = unquoteFrontMatter(value)
+		case "team":
+			file.Team = unquoteFrontMatter(value)
+		case "labels":
+			if value == "" {
+				inLabels = true
+				continue
+			}
+			for _, label := range strings.Split(strings.Trim(value, "[]"), ",") {
+				if label = unquoteFrontMatter(label); label != "" {
+					file.Labels = append(file.Labels, label)
+				}
+			}
+		}
+	}
+
+	file.Description = strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+
+	return file, nil
+}
+
+func unquoteFrontMatter(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// applyDescriptionFile fills options from a description file. Explicit
+// --team, --estimate, and --assignee flags take precedence over front-matter.
+func applyDescriptionFile(options *CreateOptions, file DescriptionFile) {
+	options.Title = file.Title
+	options.Description = file.Description
+	options.Labels = file.Labels
+	if options.Assignee == "" {
+		options.Assignee = file.Assignee
+	}
+	if options.TeamName == "" {
+		options.TeamName = file.Team
+	}
+	if options.Estimate == "" {
+		options.Estimate = file.Estimate
+	}
+}
+
+// resolveLabels matches names to labelMap case-insensitively, ignoring
+// surrounding whitespace, and returns them as Linear spells them. Every name
+// that doesn't match is listed in the error.
+func resolveLabels(names []string, labelMap map[string]string, teamName string) ([]string, error) {
+	resolved, missing := matchLabels(names, labelMap)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("labels not found in %s: %s", teamName, strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
+
+// optionLabels resolves labels given with --labels or front matter. Names the
+// team doesn't have are skipped with a warning, or rejected with --strict.
+func optionLabels(names []string, labelMap map[string]string, teamName string) ([]string, error) {
+	resolved, missing := matchLabels(names, labelMap)
+	if len(missing) > 0 {
+		if strictLabels {
+			return nil, fmt.Errorf("labels not found in %s: %s (pass --create-missing-labels to create them)", teamName, strings.Join(missing, ", "))
+		}
+		fmt.Fprintf(os.Stderr, infoSymbol+" Skipping labels not found in %s: %s\n", teamName, strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
+
+// matchLabels splits names into those labelMap has, as Linear spells them,
+// and those it doesn't.
+func matchLabels(names []string, labelMap map[string]string) (resolved, missing []string) {
+	resolved = make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := labelMap[name]; ok {
+			resolved = append(resolved, name)
+			continue
+		}
+
+		match := ""
+		for label := range labelMap {
+			if strings.EqualFold(label, name) {
+				match = label
+				break
+			}
+		}
+		if match == "" {
+			missing = append(missing, name)
+			continue
+		}
+		resolved = append(resolved, match)
+	}
+
+	return resolved, missing
+}
+
+// createMissingLabels creates the names in the team that labels doesn't have
+// and returns labels with them added, updating the label cache.
+func createMissingLabels(ctx context.Context, apiKey, teamId string, labels []Label, names []string) ([]Label, error) {
+	_, labelMap := labelOptions(labels)
+	_, missing := matchLabels(names, labelMap)
+	if len(missing) == 0 {
+		return labels, nil
+	}
+	if err := requireAPIKey(apiKey, "--create-missing-labels"); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation IssueLabelCreate($input: IssueLabelCreateInput!) {
+			issueLabelCreate(input: $input) {
+				success
+				issueLabel {
+					id
+					name
+					color
+				}
+			}
+		}
+	`
+
+	labels = slices.Clone(labels)
+	for _, name := range missing {
+		result, err := makeLinearRequest(ctx, apiKey, mutation, map[string]interface{}{
+			"input": map[string]interface{}{
+				"name":   name,
+				"teamId": teamId,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating label %q: %w", name, err)
+		}
+		data, _ := result["data"].(map[string]interface{})
+		created, _ := data["issueLabelCreate"].(map[string]interface{})
+		label, _ := created["issueLabel"].(map[string]interface{})
+		if label == nil {
+			return nil, fmt.Errorf("creating label %q: no label returned", name)
+		}
+		labels = append(labels, Label{ID: getString(label, "id"), Name: getString(label, "name"), Color: getString(label, "color")})
+		infof("Created label %s\n", name)
+	}
+	saveToCache("labels-"+teamId, labels)
+
+	return labels, nil
+}
+
+// findUser matches a user by id or email, then by name, case-insensitively.
+// Names aren't unique, so a name shared by several users is an error.
+func findUser(users []User, value string) (*User, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "me") {
+		return findViewer(users)
+	}
+	for i, user := range users {
+		if user.ID == value || (user.Email != "" && strings.EqualFold(user.Email, value)) {
+			return &users[i], nil
+		}
+	}
+
+	var matches []*User
+	for i, user := range users {
+		if strings.EqualFold(user.Name, value) {
+			matches = append(matches, &users[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no user matches %s", value)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d users are named %s; use an email instead", len(matches), value)
+	}
+}
+
+// findViewer returns the viewer's entry in users, or a stand-in built from
+// the cached viewer when the list (e.g. team members only) leaves them out.
+func findViewer(users []User) (*User, error) {
+	if viewer.ID == "" {
+		return nil, errors.New(`can't resolve "me": the current user isn't known (OAuth session or offline)`)
+	}
+	for i, user := range users {
+		if user.ID == viewer.ID {
+			return &users[i], nil
+		}
+	}
+
+	return &User{ID: viewer.ID, Name: viewer.Name}, nil
+}
+
+// resolveAssignee picks the assignee id. Precedence: --unassigned, then
+// --assignee (or front-matter), then the assignee last saved for the team,
+// then the team's configured default, else no assignee.
+func resolveAssignee(users []User, savedId, teamDefault string, options CreateOptions) (string, error) {
+	switch {
+	case options.Unassigned:
+		return "", nil
+	case options.Assignee != "":
+		user, err := findUser(users, options.Assignee)
+		if err != nil {
+			return "", err
+		}
+		return user.ID, nil
+	case savedId != "":
+		return savedId, nil
+	case teamDefault != "":
+		user, err := findUser(users, teamDefault)
+		if err != nil {
+			return "", fmt.Errorf("default assignee: %w", err)
+		}
+		return user.ID, nil
+	}
+
+	return "", nil
+}
+
+// warnSkippedDefaultSubscribers notes on stderr that a team's configured
+// default subscribers won't be added, since OAuth sessions can't subscribe.
+func warnSkippedDefaultSubscribers(teamDefaults []string, options CreateOptions) {
+	if len(teamDefaults) == 0 || options.NoSubscribers {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, infoSymbol+" Skipping the team's default subscribers: they require a Linear API key (LINEAR_API_KEY)")
+}
+
+// resolveSubscribers picks the subscriber ids. Precedence: --no-subscribers,
+// then the subscribers last saved for the team, then the team's configured
+// defaults, else none.
+func resolveSubscribers(users []User, savedIds, teamDefaults []string, options CreateOptions) ([]string, error) {
+	switch {
+	case options.NoSubscribers:
+		return nil, nil
+	case len(savedIds) > 0:
+		return savedIds, nil
+	}
+
+	var subscriberIds []string
+	for _, subscriber := range teamDefaults {
+		user, err := findUser(users, subscriber)
+		if err != nil {
+			return nil, fmt.Errorf("default subscriber: %w", err)
+		}
+		if !slices.Contains(subscriberIds, user.ID) {
+			subscriberIds = append(subscriberIds, user.ID)
+		}
+	}
+
+	return subscriberIds, nil
+}
+
+// resolveEstimate maps an estimate value or size name (e.g. "M") to one of
+// the option values for estimateType.
+func resolveEstimate(estimateType int, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	options := getEstimateOptions(estimateType)
+	allowed := make([]string, len(options))
+	for i, option := range options {
+		if option.Value == value {
+			return option.Value, nil
+		}
+		name, _, _ := strings.Cut(option.Key, " - ")
+		if strings.EqualFold(name, value) {
+			return option.Value, nil
+		}
+		allowed[i] = option.Value
+	}
+
+	return "", fmt.Errorf("estimate %q is not allowed (valid: %s)", value, strings.Join(allowed, ", "))
+}
+
+type importTeamData struct {
+	Labels       []Label
+	LabelMap     map[string]string
+	Users        []User
+	EstimateType int
+	StatusId     string
+}
+
+func runImport(ctx context.Context, apiKey, path string, options CreateOptions) {
+	ctx = withAPIKeyRotation(ctx)
+	tickets, err := parseImportFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	if len(tickets) == 0 {
+		infof("No tickets found in %s\n", path)
+		return
+	}
+
+	teams, err := loadTeams(ctx, apiKey)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching teams: %v\n", err)
+		os.Exit(1)
+	}
+
+	defaultTeam := options.TeamName
+	if defaultTeam == "" {
+		selections := loadUserSelections()
+		defaultTeam = selections.TeamId
+		if team := repoDefaultTeam(teams, selections); team != nil {
+			defaultTeam = team.ID
+		}
+	}
+
+	teamData := make(map[string]*importTeamData)
+	loadTeamData := func(teamId string) (*importTeamData, error) {
+		if data, ok := teamData[teamId]; ok {
+			return data, nil
+		}
+		labels, err := loadTeamLabels(ctx, apiKey, teamId)
+		if err != nil {
+			return nil, fmt.Errorf("fetching labels: %w", err)
+		}
+		users, err := loadTeamUsers(ctx, apiKey, teamId)
+		if err != nil {
+			return nil, fmt.Errorf("fetching users: %w", err)
+		}
+		_, labelMap := labelOptions(labels)
+		data := &importTeamData{
+			Labels:       labels,
+			LabelMap:     labelMap,
+			Users:        users,
+			EstimateType: detectEstimateType(ctx, apiKey, teamId, options.EstimateType),
+		}
+		if options.EstimateTypeSet {
+			data.EstimateType = options.EstimateType
+		}
+		if options.StatusType != "" {
+			states, err := loadWorkflowStates(ctx, apiKey, teamId)
+			if err != nil {
+				return nil, fmt.Errorf("fetching workflow states: %w", err)
+			}
+			if data.StatusId, err = statusFromType(states, options.StatusType, "this team"); err != nil {
+				return nil, err
+			}
+		}
+		teamData[teamId] = data
+		return data, nil
+	}
+
+	// prepareTicket resolves a row against its team without creating anything
+	prepareTicket := func(row ImportTicket) (LinearTicket, *importTeamData, error) {
+		title := strings.TrimSpace(row.Title)
+		if title == "" {
+			return LinearTicket{}, nil, fmt.Errorf("title cannot be empty")
+		}
+		title, err := fitTitle(title, options.TruncateTitle)
+		if err != nil {
+			return LinearTicket{}, nil, err
+		}
+
+		teamName := row.Team
+		if teamName == "" {
+			teamName = defaultTeam
+		}
+		if teamName == "" {
+			return LinearTicket{}, nil, fmt.Errorf("no team given; add a team column, pass --team, or run 'lnr set-team'")
+		}
+		team := matchTeam(teams, teamName)
+		if team == nil {
+			return LinearTicket{}, nil, fmt.Errorf("team not found: %s", teamName)
+		}
+
+		data, err := loadTeamData(team.ID)
+		if err != nil {
+			return LinearTicket{}, nil, err
+		}
+
+		rowLabels, err := resolveLabels(row.Labels, data.LabelMap, team.Name)
+		if err != nil {
+			return LinearTicket{}, nil, err
+		}
+		if err := exclusiveLabelConflict(rowLabels, data.Labels); err != nil {
+			return LinearTicket{}, nil, err
+		}
+
+		assigneeId := ""
+		if row.Assignee != "" {
+			user, err := findUser(data.Users, row.Assignee)
+			if err != nil {
+				return LinearTicket{}, nil, fmt.Errorf("assignee in %s: %w", team.Name, err)
+			}
+			assigneeId = user.ID
+		}
+
+		estimate, err := resolveEstimate(data.EstimateType, string(row.Estimate))
+		if err != nil {
+			return LinearTicket{}, nil, err
+		}
+
+		return LinearTicket{
+			Title:       title,
+			Description: row.Description,
+			TeamId:      team.ID,
+			Labels:      rowLabels,
+			Estimate:    estimate,
+			AssigneeId:  assigneeId,
+			StatusId:    data.StatusId,
+			Fields:      options.Fields,
+			Position:    options.Position,
+		}, data, nil
+	}
+
+	// Check every row before creating any, so a bad file fails up front
+	// instead of part-way through the batch
+	prepared := make([]LinearTicket, len(tickets))
+	preparedData := make([]*importTeamData, len(tickets))
+	invalid := 0
+	for i, row := range tickets {
+		ticket, data, err := prepareTicket(row)
+		if err != nil {
+			exitOnCancel(err)
+			invalid++
+			fmt.Fprintf(os.Stderr, errorSymbol+" #%d %q: %v\n", i+1, row.Title, err)
+			continue
+		}
+		prepared[i], preparedData[i] = ticket, data
+	}
+	if invalid > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d of %d tickets in %s are invalid; nothing was imported\n", invalid, len(tickets), path)
+		os.Exit(1)
+	}
+
+	failures := 0
+	var created []CreatedIssue
+	for i, row := range tickets {
+		issue, err := createLinearTicket(ctx, apiKey, prepared[i], preparedData[i].LabelMap)
+		if err != nil {
+			exitOnCancel(err)
+			failures++
+			fmt.Fprintf(os.Stderr, errorSymbol+" #%d %q: %v\n", i+1, row.Title, err)
+			continue
+		}
+		recordHistory(issue, prepared[i].TeamId)
+		created = append(created, issue)
+
+		if quietOutput {
+			fmt.Println(issue.Identifier)
+		} else {
+			fmt.Printf(successSymbol+" %s %s\n", issue.Identifier, strings.TrimSpace(row.Title))
+		}
+	}
+
+	writeOutputFile(options, created...)
+	if !quietOutput {
+		fmt.Printf("\nImported %d of %d tickets (%d failed)\n", len(tickets)-failures, len(tickets), failures)
+	}
+	if failures > 0 {
+		os.Exit(1)
+	}
+}
+
+// runFlush creates tickets queued with --offline, removing each from the
+// queue as soon as it's created so a failure part-way keeps the rest.
+func runFlush(ctx context.Context, apiKey string) {
+	queue, err := loadQueue()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error reading queue: %v\n", err)
+		os.Exit(1)
+	}
+	if len(queue) == 0 {
+		infof("No queued tickets\n")
+		return
+	}
+
+	failed := 0
+	for _, queued := range queue {
+		issue, err := createLinearTicket(ctx, apiKey, queued.Ticket, queued.LabelIDs)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" %q: %v\n", queued.Ticket.Title, err)
+			failed++
+			continue
+		}
+
+		// Drop only this ticket; another run may have queued more since
+		err = updateQueue(func(current []QueuedTicket) []QueuedTicket {
+			return slices.DeleteFunc(current, func(other QueuedTicket) bool {
+				return other.Ticket.ID == queued.Ticket.ID && other.QueuedAt.Equal(queued.QueuedAt)
+			})
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error updating queue: %v\n", err)
+			os.Exit(1)
+		}
+		recordHistory(issue, queued.Ticket.TeamId)
+		followUps := CreateOptions{
+			AttachURL:   queued.AttachURL,
+			AttachTitle: queued.AttachTitle,
+			Blocks:      queued.Blocks,
+			BlockedBy:   queued.BlockedBy,
+		}
+		attachLinkFromOptions(ctx, apiKey, issue, followUps)
+		relateIssuesFromOptions(ctx, apiKey, issue, followUps)
+
+		if quietOutput {
+			fmt.Println(issue.Identifier)
+		} else {
+			fmt.Printf(successSymbol+" %s %s (queued %s)\n", issue.Identifier, queued.Ticket.Title, timeAgo(queued.QueuedAt, time.Now()))
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d queued tickets failed and remain queued\n", failed, len(queue))
+		os.Exit(1)
+	}
+}
+
+func runHistory(jsonOutput bool) {
+	entries, err := loadHistory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		if entries == nil {
+			entries = []HistoryEntry{}
+		}
+		jsonData, err := json.Marshal(entries)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No tickets recorded yet")
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		fmt.Printf("%-10s  %-10s %s  %s\n", timeAgo(entry.CreatedAt, now), entry.Identifier, entry.Title, entry.URL)
+	}
+}
+
+func runConfigure(ctx context.Context, apiKey string, estimateType int) {
+	requireTerminal("configure needs an interactive terminal")
+	fmt.Println("Configure default team, labels, estimate, and status")
+	runSetTeam(ctx, apiKey)
+	runSetLabels(ctx, apiKey)
+	runSetEstimate(estimateType)
+	runSetStatus(ctx, apiKey)
+}
+
+func fallbackIssueBranchName(issue Issue) string {
+	return issueBranchName(issue.Identifier, issue.Title, issue.BranchName)
+}
+
+func issueSearchScore(issue Issue, term string) int {
+	query := strings.ToLower(strings.TrimSpace(term))
+	if query == "" {
+		return 0
+	}
+
+	identifier := strings.ToLower(issue.Identifier)
+	title := strings.ToLower(issue.Title)
+	searchText := identifier + " " + title
+	if query == identifier {
+		return 1000
+	}
+	if strings.Contains(identifier, query) {
+		return 900 + len(query)
+	}
+	if strings.Contains(title, query) {
+		return 700 + len(query)
+	}
+	if strings.Contains(searchText, query) {
+		return 600 + len(query)
+	}
+
+	score := 0
+	queryIndex := 0
+	for _, r := range searchText {
+		if queryIndex >= len(query) {
+			break
+		}
+		if byte(r) == query[queryIndex] {
+			score++
+			queryIndex++
+		}
+	}
+	if queryIndex != len(query) {
+		return 0
+	}
+
+	return score
+}
+
+func findBestIssue(issues []Issue, term string) (Issue, bool) {
+	var bestIssue Issue
+	bestScore := 0
+	for _, issue := range issues {
+		score := issueSearchScore(issue, term)
+		if score > bestScore {
+			bestScore = score
+			bestIssue = issue
+		}
+	}
+
+	return bestIssue, bestScore > 0
+}
+
+func outputIssue(issue Issue, jsonOutput bool) {
+	branchName := fallbackIssueBranchName(issue)
+	issue.BranchName = branchName
+	if jsonOutput {
+		jsonData, err := json.Marshal(issue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	if err := clipboard.WriteAll(branchName); err != nil {
+		fmt.Println(branchName)
+		fmt.Fprintln(os.Stderr, errorSymbol+" "+clipboardErrorMessage(err))
+		return
+	}
+
+	fmt.Println(branchName)
+}
+
+func runIssueSearch(ctx context.Context, apiKey, searchTerm string, jsonOutput bool) {
+	selections := loadUserSelections()
+	teamId := requireDefaultTeam(selections)
+
+	issues, err := withSpinner("Loading issues"+ellipsisSymbol, func() ([]Issue, error) {
+		return fetchTeamIssues(ctx, apiKey, teamId)
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching issues: %v\n", err)
+		os.Exit(1)
+	}
+	if len(issues) == 0 {
+		fmt.Println("No issues found for the default team")
+		return
+	}
+	if searchTerm != "" {
+		issue, found := findBestIssue(issues, searchTerm)
+		if !found {
+			fmt.Fprintf(os.Stderr, "No issue matched %q\n", searchTerm)
+			os.Exit(1)
+		}
+
+		outputIssue(issue, jsonOutput)
+		return
+	}
+
+	issueByKey := make(map[string]Issue, len(issues))
+	options := make([]huh.Option[string], len(issues))
+	for i, issue := range issues {
+		key := issue.Identifier + " " + issue.Title
+		issueByKey[key] = issue
+		options[i] = huh.Option[string]{Key: key, Value: key}
+	}
+
+	requireTerminal("pass a search term to pick the best match without a prompt")
+	selectedIssueKey := ""
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Issue").
+				Description("Filter issues from the default team").
+				Options(options...).
+				Filtering(true).
+				Value(&selectedIssueKey),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Issue selection")
+	}
+
+	issue := issueByKey[selectedIssueKey]
+	outputIssue(issue, jsonOutput)
+}
+
+func runSearch(ctx context.Context, apiKey string, options SearchOptions) {
+	teamId := ""
+	if options.TeamName != "" {
+		team, err := resolveTeam(ctx, apiKey, options.TeamName)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error resolving team: %v\n", err)
+			os.Exit(1)
+		}
+		teamId = team.ID
+	}
+
+	issues, err := withSpinner("Searching issues"+ellipsisSymbol, func() ([]Issue, error) {
+		return searchIssues(ctx, apiKey, options.Query, teamId, options.Limit)
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error searching issues: %v\n", err)
+		os.Exit(1)
+	}
+
+	if options.JSONOutput {
+		jsonData, err := json.Marshal(issues)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	if len(issues) == 0 {
+		fmt.Fprintf(os.Stderr, "No issues matched %q\n", options.Query)
+		os.Exit(1)
+	}
+	for _, issue := range issues {
+		fmt.Printf("%-10s %s\n", issue.Identifier, issue.Title)
+	}
+}
+
+func runAuth(ctx context.Context, args []string) {
+	if len(args) == 0 || hasHelpArg(args) {
+		printAuthUsage()
+		return
+	}
+
+	switch args[0] {
+	case "login":
+		if err := clearOAuthTokenCache(); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error clearing saved OAuth token: %v\n", err)
+			os.Exit(1)
+		}
+		if _, err := runDCRLogin(ctx, oauthScopes()); err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error signing in to Linear: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(successSymbol + " Linear OAuth token saved")
+	case "logout":
+		if err := clearOAuthTokenCache(); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error clearing saved OAuth token: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(successSymbol + " Linear OAuth token cleared")
+	default:
+		fmt.Printf("Unknown auth command: %s\n\n", args[0])
+		printAuthUsage()
+		os.Exit(1)
+	}
+}
+
+// exitOnCancel exits quietly when err was caused by Ctrl-C cancelling the request context.
+func exitOnCancel(err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "\nCancelled")
+		os.Exit(exitCancelled)
+	}
+}
+
+// requireTerminal exits with hint when stdin isn't a terminal, so a form
+// launched from CI or a pipe fails fast instead of waiting for input forever.
+func requireTerminal(hint string) {
+	if isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, errorSymbol+" No TTY on stdin; "+hint)
+	os.Exit(1)
+}
+
+// exitOnFormError exits after a form fails: with exitCancelled when the user
+// aborted it (Esc or Ctrl-C), and 1 for anything else.
+func exitOnFormError(err error, what string) {
+	if errors.Is(err, huh.ErrUserAborted) || errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "%s cancelled\n", what)
+		os.Exit(exitCancelled)
+	}
+
+	fmt.Fprintf(os.Stderr, errorSymbol+" %s failed: %v\n", what, err)
+	os.Exit(1)
+}
+
+// infof prints an informational note to stderr unless --quiet is set, so
+// piped output carries only the result.
+func infof(format string, args ...interface{}) {
+	if quietOutput {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, infoSymbol+" "+format, args...)
+}
+
+var spinnerFrames = []string{"\u280b", "\u2819", "\u2839", "\u2838", "\u283c", "\u2834", "\u2826", "\u2827", "\u2807", "\u280f"}
+
+// withSpinner shows a spinner on stderr while fn runs. It stays silent for
+// fast (cached) loads, when stderr isn't a terminal, and in verbose mode.
+func withSpinner[T any](title string, fn func() (T, error)) (T, error) {
+	if quietOutput || verbosity > 0 || !isatty.IsTerminal(os.Stderr.Fd()) {
+		return fn()
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		select {
+		case <-done:
+			return
+		case <-time.After(150 * time.Millisecond):
+		}
+
+		ticker := time.NewTicker(80 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			fmt.Fprintf(os.Stderr, "\r%s %s", spinnerFrames[frame%len(spinnerFrames)], title)
+			select {
+			case <-done:
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	result, err := fn()
+	close(done)
+	<-stopped
+
+	return result, err
+}
+
+func isHelpArg(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "--help"
+}
+
+// createCommandArgs drops a leading create subcommand so its flags parse like
+// those of bare lnr, which stays an alias for create.
+func createCommandArgs(args []string) []string {
+	if len(args) > 0 && args[0] == "create" {
+		return args[1:]
+	}
+
+	return args
+}
+
+func hasHelpArg(args []string) bool {
+	for _, arg := range args {
+		if isHelpArg(arg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// promptQuickCapture asks for just a title and an optional description for
+// "lnr quick" without a title; everything else comes from saved defaults.
+func promptQuickCapture(options CreateOptions) (string, string, error) {
+	title := ""
+	description := options.Description
+	if options.DescriptionTemplate != "" {
+		description = options.DescriptionTemplate
+	}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Ticket Title").
+				Value(&title).
+				Validate(validateTitle(options)),
+			huh.NewText().
+				Title("Description").
+				Description("Optional").
+				Value(&description).
+				Lines(3),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return "", "", err
+	}
+
+	return title, strings.TrimSpace(description), nil
+}
+
+// runWatch keeps asking for titles and files each one like lnr quick, with
+// the same team and saved defaults, until an empty title or Ctrl+C. An API
+// error ends the session.
+func runWatch(ctx context.Context, apiKey string, options CreateOptions) {
+	requireTerminal("--watch needs an interactive terminal")
+	if err := validateWatchOptions(options); err != nil {
+		fmt.Fprintln(os.Stderr, errorSymbol+" "+err.Error())
+		os.Exit(1)
+	}
+	// One identifier per ticket keeps the session's scrollback readable
+	// and leaves the clipboard alone
+	quietOutput = !options.JSONOutput
+
+	filed, err := watchTickets(options, promptWatchTitle, func(title string, options CreateOptions) {
+		runQuickCreate(ctx, apiKey, title, options)
+	})
+	if err != nil {
+		exitOnFormError(err, "Form")
+	}
+
+	fmt.Fprintf(os.Stderr, successSymbol+" Filed %d tickets\n", filed)
+}
+
+// validateWatchOptions rejects options that can't apply to every ticket of a
+// --watch session.
+func validateWatchOptions(options CreateOptions) error {
+	if options.IdempotencyKey != "" {
+		// Every ticket would reserve the same issue id
+		return errors.New("--watch can't be combined with --idempotency-key")
+	}
+
+	return nil
+}
+
+// watchTickets asks for titles with prompt and files each one with file until
+// an empty title or an aborted prompt, returning how many were filed.
+func watchTickets(options CreateOptions, prompt func(int, CreateOptions) (string, error), file func(string, CreateOptions)) (int, error) {
+	filed := 0
+	for {
+		title, err := prompt(filed+1, options)
+		if errors.Is(err, huh.ErrUserAborted) {
+			return filed, nil
+		}
+		if err != nil {
+			return filed, err
+		}
+		if strings.TrimSpace(title) == "" {
+			return filed, nil
+		}
+		file(title, options)
+		options.clearFirstTicketOnly()
+		filed++
+	}
+}
+
+// promptWatchTitle asks for the nth title in a --watch session. Unlike
+// promptQuickCapture, an empty title is allowed: it ends the session.
+func promptWatchTitle(n int, options CreateOptions) (string, error) {
+	title := ""
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Ticket #%d", n)).
+				Description("Enter to file, empty to finish").
+				Value(&title).
+				Validate(func(s string) error {
+					// An empty title ends the session
+					if strings.TrimSpace(s) == "" {
+						return nil
+					}
+					return validateTitle(options)(s)
+				}),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return "", err
+	}
+
+	return title, nil
+}
+
+func printQuickUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr quick")
+	fmt.Println("  lnr quick [--json] <title>")
+	fmt.Println("  lnr [--json] --quick <title>")
+	fmt.Println("  lnr --watch")
+	fmt.Println("  lnr --description-file <ticket.md> quick [title]")
+}
+
+func printIssueUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr issue [--json] [search term]")
+	fmt.Println("  lnr [--json] issue [search term]")
+}
+
+func printCompletionUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr completion bash")
+	fmt.Println("  lnr completion zsh")
+}
+
+func printDescribeTeamUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr describe-team [--json] [team]")
+}
+
+func printSearchUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr search [--json] [--team <team>] [--limit <n>] <query>")
+}
+
+func printStatusUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr status <identifier> <state name>")
+	fmt.Println("  lnr status --to <state name> < identifiers.txt")
+}
+
+func printCacheUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr cache list [--json]")
+	fmt.Println("  lnr cache path")
+	fmt.Println("  lnr cache clear [--dry-run] <teams|labels|users|members|states|settings|templates|projects> [team]")
+	fmt.Println("  lnr cache clear [--dry-run] milestones")
+	fmt.Println("  lnr cache clear [--dry-run] <team|key>")
+}
+
+func printFlushUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr flush")
+}
+
+func printHistoryUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr history [--json]")
+}
+
+func printAuthUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  lnr auth login")
+	fmt.Println("  lnr auth logout")
+}
+
+func parseQuickArgs(args []string) (string, bool) {
+	var titleParts []string
+	jsonOutput := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			titleParts = append(titleParts, arg)
+		}
+	}
+
+	return strings.Join(titleParts, " "), jsonOutput
+}
+
+func parseIssueArgs(args []string) (string, bool) {
+	var searchParts []string
+	jsonOutput := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			searchParts = append(searchParts, arg)
+		}
+	}
+
+	return strings.Join(searchParts, " "), jsonOutput
+}
+
+func parseSearchArgs(args []string) (SearchOptions, error) {
+	options := SearchOptions{Limit: 20}
+	var queryParts []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--json":
+			options.JSONOutput = true
+		case "--team", "--limit":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return options, fmt.Errorf("%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			if name == "--team" {
+				options.TeamName = value
+				continue
+			}
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 1 {
+				return options, fmt.Errorf("--limit must be a positive number")
+			}
+			options.Limit = limit
+		default:
+			queryParts = append(queryParts, arg)
+		}
+	}
+	options.Query = strings.Join(queryParts, " ")
+
+	return options, nil
+}
+
+func hasJSONArg(args []string) bool {
+	for _, arg := range args {
+		if arg == "--json" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printBashCompletion() {
+	fmt.Print(`_lnr_completion() {
+  local cur prev commands global_flags shells
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  prev="${COMP_WORDS[COMP_CWORD-1]}"
+  commands="create quick issue search status auth history flush cache describe-team configure set-team set-labels set-estimate set-status completion reset help"
+  global_flags="--clear-cache --dry-run --version --json --quick --watch --template --template-id --description-template --title-from-branch --minimal --preview --estimate --estimate-raw --estimate-type --save-snippet --team --team-all --assignee --unassigned --no-subscribers --labels --create-missing-labels --parent-auto --field --output-file --output-format --position --attach --attach-title --attach-image --include-inactive --assignee-team-only --import --description-file --description-from-clipboard --open --no-post-menu --copy-branch --slack --blocks --blocked-by --status-type --truncate-title --idempotency-key --strict --no-proxy --offline --plain --no-emoji --quiet --verbose -vv -h --help"
+  shells="bash zsh"
+
+  if [[ ${COMP_CWORD} -eq 1 ]]; then
+    COMPREPLY=( $(compgen -W "${commands} ${global_flags}" -- "${cur}") )
+    return 0
+  fi
+
+  case "${COMP_WORDS[1]}" in
+    create)
+      COMPREPLY=( $(compgen -W "${global_flags}" -- "${cur}") )
+      return 0
+      ;;
+    quick)
+      COMPREPLY=( $(compgen -W "--json -h --help" -- "${cur}") )
+      return 0
+      ;;
+    issue)
+      COMPREPLY=( $(compgen -W "--json -h --help" -- "${cur}") )
+      return 0
+      ;;
+    search)
+      COMPREPLY=( $(compgen -W "--json --team --limit -h --help" -- "${cur}") )
+      return 0
+      ;;
+    status)
+      COMPREPLY=( $(compgen -W "--to -h --help" -- "${cur}") )
+      return 0
+      ;;
+    auth)
+      COMPREPLY=( $(compgen -W "login logout -h --help" -- "${cur}") )
+      return 0
+      ;;
+    history|describe-team)
+      COMPREPLY=( $(compgen -W "--json -h --help" -- "${cur}") )
+      return 0
+      ;;
+    cache)
+      COMPREPLY=( $(compgen -W "list path clear --json --dry-run -h --help" -- "${cur}") )
+      return 0
+      ;;
+    completion)
+      COMPREPLY=( $(compgen -W "${shells}" -- "${cur}") )
+      return 0
+      ;;
+  esac
+}
+
+complete -F _lnr_completion lnr
+`)
+}
+
+func printZshCompletion() {
+	fmt.Print(`#compdef lnr
+
+_lnr() {
+  local -a commands
+  commands=(
+    'create:Create a Linear issue with the interactive form (the default)'
+    'quick:Create a Linear issue from a title'
+    'issue:Find an issue in the default team'
+    'search:Search issues by text'
+    'status:Move an issue to another workflow state'
+    'auth:Manage OAuth sign-in'
+    'history:Show tickets created with lnr'
+    'flush:Create tickets queued with --offline'
+    'cache:Inspect cached data'
+    'describe-team:Show how a team is configured'
+    'configure:Configure default team, labels, estimate, and status'
+    'set-team:Set the default team'
+    'set-labels:Set default labels'
+    'set-estimate:Set the default estimate'
+    'set-status:Set the default status'
+    'completion:Generate shell completions'
+    'reset:Clear cached API data and saved defaults'
+    'help:Show help'
+  )
+
+  case $words[2] in
+    quick)
+      _arguments '--json[Output JSON]' '-h[Show help]' '--help[Show help]' '*:title:'
+      ;;
+    issue)
+      _arguments '--json[Output JSON]' '-h[Show help]' '--help[Show help]' '*:search term:'
+      ;;
+    search)
+      _arguments '--json[Output JSON]' '--team[Team key, name, or id]:team:' '--limit[Maximum results]:limit:' '-h[Show help]' '--help[Show help]' '*:query:'
+      ;;
+    status)
+      _arguments '--to[Move identifiers read from stdin to this state]:state name:' '1:identifier:' '*:state name:' '-h[Show help]' '--help[Show help]'
+      ;;
+    auth)
+      _arguments '1:auth command:(login logout)' '-h[Show help]' '--help[Show help]'
+      ;;
+    history)
+      _arguments '--json[Output JSON]' '-h[Show help]' '--help[Show help]'
+      ;;
+    describe-team)
+      _arguments '--json[Output JSON]' '-h[Show help]' '--help[Show help]' '*:team:'
+      ;;
+    cache)
+      _arguments '1:cache command:(list path clear)' '--json[Output JSON]' '--dry-run[List what clear would remove]' '-h[Show help]' '--help[Show help]'
+      ;;
+    completion)
+      _arguments '1:shell:(bash zsh)'
+      ;;
+    *)
+      _arguments '--clear-cache[Clear cached API data and saved defaults]' '--dry-run[List what --clear-cache would remove]' '--version[Print version information]' '--json[Output JSON]' '--quick[Create a Linear issue from a title]' '--watch[Keep creating issues from titles until an empty one]' '--template[Pre-fill the form from a named template]:template:' '--template-id[Apply a Linear issue template]:template:' '--description-template[Pre-fill the description from a named scaffold]:template:' '--title-from-branch[Pre-fill the title from the current git branch]' '--minimal[Ask only for the title and description]' '--preview[Confirm with a rendered description preview]' '--estimate[Estimate to use]:estimate:' '--estimate-raw[Raw estimate sent as is]:points:' '--estimate-type[Estimate scale to use]:estimate type:(none tshirt fibonacci points linear exponential)' '--save-snippet[Save the description as the team snippet]' '--team[Team key, name, or id]:team:' '--team-all[Pick a label from every team first]' '--assignee[Assignee email or unique name]:email:' '--unassigned[Leave the issue unassigned]' '--no-subscribers[Add no subscribers]' '--labels[Comma-separated label names]:labels:' '--create-missing-labels[Create labels the team does not have]' '--parent-auto[Nest under the issue for the current branch]' '*--field[Extra issueCreate field]:name=value:' '--output-file[Write the created issue to a file]:file:_files' '--output-format[Format for --output-file]:format:(plain json)' '--position[Place the issue at the top or bottom]:position:(top bottom)' '--attach[Attach a link to the created issue]:url:' '--attach-title[Title for the attached link]:title:' '*--attach-image[Upload an image into the description]:file:_files' '--include-inactive[Include deactivated users]' '--assignee-team-only[List only team members as assignees]' '--import[Create tickets in bulk from a file]:file:_files -g "*.(json|csv)"' '--description-file[Read the description from a markdown file]:file:_files -g "*.md"' '--description-from-clipboard[Use the clipboard as the description]' '--open[Open the created issue in the browser]' '--no-post-menu[Skip the post-creation menu]' '--copy-branch[Copy the branch name after creating]' '--slack[Copy a Slack link after creating]' '--blocks[Issues the created issue blocks]:issues:' '--blocked-by[Issues the created issue is blocked by]:issues:' '--status-type[Start in the first state of this type]:status type:(triage backlog unstarted started completed canceled)' '--truncate-title[Cut over-long titles instead of rejecting them]' '--idempotency-key[Reuse the same issue id on retries]:key:' '--strict[Fail on labels missing from the team]' '--no-proxy[Ignore HTTP(S)_PROXY]' '--offline[Use cached data and queue tickets]' '--plain[Use plain ASCII output]' '--no-emoji[Use plain ASCII output]' '--quiet[Print only the created identifier]' '--verbose[Log API requests to stderr]' '-vv[Log API requests and response bodies to stderr]' '1:command:->commands'
+      if [[ $state == commands ]]; then
+        _describe 'commands' commands
+      fi
+      ;;
+  esac
+}
+
+_lnr "$@"
+`)
+}
+
+func versionString() string {
+	buildVersion, buildCommit, buildDate := version, commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if buildVersion == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			buildVersion = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if buildCommit == "none" {
+					buildCommit = setting.Value
+				}
+			case "vcs.time":
+				if buildDate == "unknown" {
+					buildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	return fmt.Sprintf("lnr %s (commit %s, built %s, %s)", buildVersion, buildCommit, buildDate, runtime.Version())
+}
+
+func runCompletion(shell string) {
+	switch shell {
+	case "bash":
+		printBashCompletion()
+	case "zsh":
+		printZshCompletion()
+	default:
+		printCompletionUsage()
+		os.Exit(1)
+	}
+}
+
+func main() {
+	// Parse command-line flags
+	clearCacheFlag := flag.Bool("clear-cache", false, "Clear cached API data and saved defaults")
+	dryRunFlag := flag.Bool("dry-run", false, "With --clear-cache, reset, or cache clear, list what would be removed without deleting it")
+	versionFlag := flag.Bool("version", false, "Print version and build information")
+	quickTitleFlag := flag.String("quick", "", "Create a Linear issue from a title and print the branch name")
+	watchFlag := flag.Bool("watch", false, "Keep asking for titles and create each one like quick until an empty title")
+	jsonOutputFlag := flag.Bool("json", false, "Output supported command result as JSON")
+	templateFlag := flag.String("template", "", "Pre-fill the form from a named template in templates.json")
+	minimalFlag := flag.Bool("minimal", false, "Ask only for the title and description, using saved choices for everything else")
+	titleFromBranchFlag := flag.Bool("title-from-branch", false, "Pre-fill the title from the current git branch name")
+	estimateTypeFlag := flag.String("estimate-type", "", "Estimate scale to use: none, tshirt, fibonacci, points, linear, or exponential")
+	saveSnippetFlag := flag.Bool("save-snippet", false, "Save the description as the team's reusable snippet")
+	estimateFlag := flag.String("estimate", "", "Estimate to use (a value such as 3, a size such as M, or auto for the team default)")
+	estimateRawFlag := flag.String("estimate-raw", "", "Estimate as a raw integer, sent as is without checking the team's estimate scale")
+	descriptionTemplateFlag := flag.String("description-template", "", "Pre-fill only the description from ~/.config/lnr/descriptions/<name>.md")
+	descriptionFromClipboardFlag := flag.Bool("description-from-clipboard", false, "Use the clipboard contents (e.g. a copied stack trace) as the description")
+	descriptionFileFlag := flag.String("description-file", "", "Read the description (and optional front-matter fields) from a markdown file")
+	templateIDFlag := flag.String("template-id", "", "Apply one of the team's Linear issue templates, by id or name")
+	outputFileFlag := flag.String("output-file", "", "Write the created identifier and URL to this file (for CI artifacts)")
+	positionFlag := flag.String("position", "", "Place the issue at the top or bottom of the team's manual order: top or bottom")
+	outputFormatFlag := flag.String("output-format", "plain", "Format for --output-file: plain or json")
+	var attachImageFlag repeatedFlag
+	flag.Var(&attachImageFlag, "attach-image", "Upload a local image (e.g. a screenshot) and add it to the description (repeatable)")
+	var fieldFlag repeatedFlag
+	flag.Var(&fieldFlag, "field", "Set an extra issueCreate input field as name=value (repeatable; JSON values keep their type)")
+	unassignedFlag := flag.Bool("unassigned", false, "Leave the issue unassigned, ignoring saved and team default assignees")
+	parentAutoFlag := flag.Bool("parent-auto", false, "Make the issue a sub-issue of the one named in the current git branch (e.g. ada/eng-123-fix)")
+	labelsFlag := flag.String("labels", "", "Comma-separated label names to apply; names the team doesn't have are skipped")
+	createMissingLabelsFlag := flag.Bool("create-missing-labels", false, "Create --labels names the team doesn't have yet instead of skipping them")
+	noSubscribersFlag := flag.Bool("no-subscribers", false, "Add no subscribers, ignoring saved and team default subscribers")
+	assigneeFlag := flag.String("assignee", "", "Assign the issue to a teammate by email (or unique name)")
+	teamAllFlag := flag.Bool("team-all", false, "Pick a label from every team first and file the ticket in the label's team")
+	teamFlag := flag.String("team", "", "Team key (e.g. ENG), name, or id to file the ticket in")
+	attachFlag := flag.String("attach", "", "Attach a link (e.g. a PR or Sentry issue) to the created issue")
+	attachTitleFlag := flag.String("attach-title", "", "Title for the --attach link (defaults to the URL)")
+	assigneeTeamOnlyFlag := flag.Bool("assignee-team-only", false, "List only the team's members as assignees and subscribers, not the whole organization")
+	includeInactiveFlag := flag.Bool("include-inactive", false, "Include deactivated users in the assignee and subscriber pickers")
+	noPostMenuFlag := flag.Bool("no-post-menu", false, "Exit right after creating instead of showing the post-creation menu (implied by --quiet)")
+	openFlag := flag.Bool("open", false, "Open the created issue in the browser and skip the post-creation menu")
+	previewFlag := flag.Bool("preview", false, "Confirm before creating, with an option to preview the rendered description")
+	blocksFlag := flag.String("blocks", "", "Comma-separated issues (e.g. ENG-12) the created issue blocks")
+	blockedByFlag := flag.String("blocked-by", "", "Comma-separated issues (e.g. ENG-12) the created issue is blocked by")
+	idempotencyKeyFlag := flag.String("idempotency-key", "", "Reuse the same issue id for retries with this key so a rerun can't create a duplicate")
+	strictFlag := flag.Bool("strict", false, "Fail instead of creating the issue without labels that don't exist in the team")
+	noProxyFlag := flag.Bool("no-proxy", false, "Connect to Linear directly, ignoring HTTP_PROXY and HTTPS_PROXY")
+	truncateTitleFlag := flag.Bool("truncate-title", false, "Cut titles over Linear's 255-character limit instead of rejecting them")
+	statusTypeFlag := flag.String("status-type", "", "Start the issue in the team's first state of this type: triage, backlog, unstarted, started, completed, or canceled")
+	slackFlag := flag.Bool("slack", false, "Copy a Slack-formatted link to the created issue and skip the post-creation menu")
+	copyBranchFlag := flag.Bool("copy-branch", false, "Copy the branch name (or print it when piped) and skip the post-creation menu")
+	offlineFlag := flag.Bool("offline", false, "Use only cached data and queue created tickets for later")
+	plainFlag := flag.Bool("plain", false, "Use plain ASCII output instead of emoji, box drawing, and colors")
+	noEmojiFlag := flag.Bool("no-emoji", false, "Alias for --plain")
+	quietFlag := flag.Bool("quiet", false, "Suppress decorative output and print only the created identifier")
+	verboseFlag := flag.Bool("verbose", false, "Log API requests, response status, and timing to stderr")
+	importFlag := flag.String("import", "", "Create tickets in bulk from a JSON or CSV file")
+	veryVerboseFlag := flag.Bool("vv", false, "Like --verbose, but also dump full response bodies")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr [create] [--template <name>] [--title-from-branch]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr quick [--json] <title>\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr --watch\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr --import <file.json|file.csv>\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr issue [--json] [search term]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr search [--json] [--team <team>] [--limit <n>] <query>\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr status <identifier> <state name>\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr status --to <state name> < identifiers.txt\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr auth login|logout\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr history [--json]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr flush\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr cache list|path|clear\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr describe-team [--json] [team]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr configure\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-team\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-labels\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-estimate\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr set-status\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr completion bash|zsh\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  lnr reset [--dry-run]\n\n")
+		flag.PrintDefaults()
+	}
+	// Errors exit through flag.ExitOnError, like flag.Parse
+	_ = flag.CommandLine.Parse(createCommandArgs(os.Args[1:]))
+
+	quietOutput = *quietFlag
+	if *noProxyFlag {
+		httpClient = newHTTPClient(false)
+	}
+	offlineMode = *offlineFlag
+	strictLabels = *strictFlag
+	teamMembersOnly = *assigneeTeamOnlyFlag || loadConfig().AssigneeTeamOnly
+	if *plainFlag || *noEmojiFlag || !utf8Locale() {
+		usePlainOutput()
+	}
+	if *veryVerboseFlag {
+		verbosity = 2
+	} else if *verboseFlag {
+		verbosity = 1
+	}
+
+	if *attachFlag != "" {
+		if err := validateLinkURL(*attachFlag); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --attach URL: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	blocks, err := parseIssueIdentifiers(*blocksFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --blocks: %v\n", err)
+		os.Exit(1)
+	}
+	blockedBy, err := parseIssueIdentifiers(*blockedByFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --blocked-by: %v\n", err)
+		os.Exit(1)
+	}
+
+	statusType := ""
+	if *statusTypeFlag != "" {
+		statusType, err = parseStatusType(*statusTypeFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --status-type: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *estimateRawFlag != "" {
+		if *estimateFlag != "" {
+			fmt.Fprintln(os.Stderr, errorSymbol+" --estimate and --estimate-raw can't be used together")
+			os.Exit(1)
+		}
+		if points, err := strconv.Atoi(*estimateRawFlag); err != nil || points < 0 {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --estimate-raw %q (expected a whole number of points)\n", *estimateRawFlag)
+			os.Exit(1)
+		}
+	}
+
+	if *outputFormatFlag != "plain" && *outputFormatFlag != "json" {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --output-format %q (valid: plain, json)\n", *outputFormatFlag)
+		os.Exit(1)
+	}
+
+	if *positionFlag != "" && *positionFlag != "top" && *positionFlag != "bottom" {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --position %q (valid: top, bottom)\n", *positionFlag)
+		os.Exit(1)
+	}
+
+	for _, path := range attachImageFlag {
+		if err := validateImageFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --attach-image: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fields, err := parseFields(fieldFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --field: %v\n", err)
+		os.Exit(1)
+	}
+
+	createOptions := CreateOptions{
+		TeamName:            *teamFlag,
+		Assignee:            *assigneeFlag,
+		Unassigned:          *unassignedFlag,
+		NoSubscribers:       *noSubscribersFlag,
+		CreateMissingLabels: *createMissingLabelsFlag,
+		ParentAuto:          *parentAutoFlag,
+		Fields:              fields,
+		OutputFile:          *outputFileFlag,
+		OutputFormat:        *outputFormatFlag,
+		TemplateID:          *templateIDFlag,
+		Position:            *positionFlag,
+		Images:              attachImageFlag,
+		TeamAll:             *teamAllFlag,
+		Minimal:             *minimalFlag,
+		JSONOutput:          *jsonOutputFlag,
+		AttachURL:           *attachFlag,
+		AttachTitle:         *attachTitleFlag,
+		Template:            *templateFlag,
+		TitleFromBranch:     *titleFromBranchFlag,
+		SaveSnippet:         *saveSnippetFlag,
+		Estimate:            *estimateFlag,
+		EstimateRaw:         *estimateRawFlag,
+		Open:                *openFlag || loadConfig().OpenAfterCreate,
+		Preview:             *previewFlag,
+		CopyBranch:          *copyBranchFlag,
+		NoPostMenu:          *noPostMenuFlag || *quietFlag,
+		Slack:               *slackFlag,
+		Blocks:              blocks,
+		BlockedBy:           blockedBy,
+		StatusType:          statusType,
+		TruncateTitle:       *truncateTitleFlag,
+		IdempotencyKey:      *idempotencyKeyFlag,
+		IncludeInactive:     *includeInactiveFlag,
+	}
+
+	if *descriptionFileFlag != "" {
+		content, err := os.ReadFile(*descriptionFileFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error reading description file: %v\n", err)
+			os.Exit(1)
+		}
+		file, err := parseDescriptionFile(string(content))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error parsing %s: %v\n", *descriptionFileFlag, err)
+			os.Exit(1)
+		}
+		applyDescriptionFile(&createOptions, file)
+	}
+	if *labelsFlag != "" {
+		createOptions.Labels = strings.Split(*labelsFlag, ",")
+	}
+
+	if *slackFlag && *copyBranchFlag {
+		fmt.Fprintln(os.Stderr, errorSymbol+" --slack and --copy-branch can't be used together")
+		os.Exit(1)
+	}
+
+	if *teamAllFlag && *teamFlag != "" {
+		fmt.Fprintln(os.Stderr, errorSymbol+" --team-all and --team can't be used together")
+		os.Exit(1)
+	}
+
+	if *unassignedFlag && *assigneeFlag != "" {
+		fmt.Fprintln(os.Stderr, errorSymbol+" --unassigned and --assignee can't be used together")
+		os.Exit(1)
+	}
+
+	if *descriptionTemplateFlag != "" {
+		description, err := loadDescriptionTemplate(*descriptionTemplateFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error loading description template: %v\n", err)
+			os.Exit(1)
+		}
+		createOptions.DescriptionTemplate = description
+	}
+
+	if *descriptionFromClipboardFlag {
+		if *descriptionFileFlag != "" || *descriptionTemplateFlag != "" {
+			fmt.Fprintln(os.Stderr, errorSymbol+" --description-from-clipboard can't be used with --description-file or --description-template")
+			os.Exit(1)
+		}
+		description, err := clipboardDescription()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		createOptions.Description = description
+	}
+
+	// Cancel in-flight requests on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	estimateType := estimateTypeTShirt
+	if *estimateTypeFlag != "" {
+		parsedEstimateType, err := parseEstimateType(*estimateTypeFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		estimateType = parsedEstimateType
+		createOptions.EstimateTypeSet = true
+	}
+	createOptions.EstimateType = estimateType
+
+	if *versionFlag {
+		fmt.Println(versionString())
+		return
+	}
+
+	// Handle clear cache flag
+	if *clearCacheFlag {
+		runReset(*dryRunFlag)
+		return
+	}
+	if *importFlag != "" {
+		runImport(ctx, getLinearAuthHeader(ctx), *importFlag, createOptions)
+		return
+	}
+	if *quickTitleFlag != "" {
+		runQuickCreate(ctx, getLinearAuthHeader(ctx), *quickTitleFlag, createOptions)
+		return
+	}
+	if *watchFlag {
+		runWatch(ctx, getLinearAuthHeader(ctx), createOptions)
+		return
+	}
+
+	args := flag.Args()
 	if len(args) > 0 {
 		switch args[0] {
+		case "create":
+			if len(args) > 1 {
+				// Flags after a late create would have been left unparsed
+				fmt.Fprintf(os.Stderr, errorSymbol+" Unexpected arguments after create: %s (put flags right after create)\n", strings.Join(args[1:], " "))
+				os.Exit(1)
+			}
+			runCreate(ctx, createOptions)
 		case "quick":
-			if len(args) == 1 || hasHelpArg(args[1:]) {
+			if hasHelpArg(args[1:]) {
 				printQuickUsage()
 				return
 			}
-			title, jsonOutput := parseQuickArgs(args[1:])
-			runQuickCreate(getLinearAuthHeader(), title, jsonOutput || *jsonOutputFlag)
-		case "issue":
-			if hasHelpArg(args[1:]) {
-				printIssueUsage()
+			if len(args) == 1 && createOptions.Title == "" {
+				// Without a title, capture one interactively
+				if !isatty.IsTerminal(os.Stdin.Fd()) {
+					printQuickUsage()
+					return
+				}
+				title, description, err := promptQuickCapture(createOptions)
+				if err != nil {
+					exitOnFormError(err, "Form")
+				}
+				// The form was pre-filled with any template, so its text wins
+				createOptions.Description = description
+				createOptions.DescriptionTemplate = ""
+				runQuickCreate(ctx, getLinearAuthHeader(ctx), title, createOptions)
+				return
+			}
+			title, jsonOutput := parseQuickArgs(args[1:])
+			createOptions.JSONOutput = createOptions.JSONOutput || jsonOutput
+			runQuickCreate(ctx, getLinearAuthHeader(ctx), title, createOptions)
+		case "issue":
+			if hasHelpArg(args[1:]) {
+				printIssueUsage()
+				return
+			}
+			searchTerm, jsonOutput := parseIssueArgs(args[1:])
+			runIssueSearch(ctx, getLinearAuthHeader(ctx), searchTerm, jsonOutput || *jsonOutputFlag)
+		case "search":
+			if len(args) == 1 || hasHelpArg(args[1:]) {
+				printSearchUsage()
+				return
+			}
+			searchOptions, err := parseSearchArgs(args[1:])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+				os.Exit(1)
+			}
+			if searchOptions.Query == "" {
+				printSearchUsage()
+				os.Exit(1)
+			}
+			if searchOptions.TeamName == "" {
+				searchOptions.TeamName = *teamFlag
+			}
+			searchOptions.JSONOutput = searchOptions.JSONOutput || *jsonOutputFlag
+			runSearch(ctx, getLinearAuthHeader(ctx), searchOptions)
+		case "status":
+			if hasHelpArg(args[1:]) {
+				printStatusUsage()
+				return
+			}
+			if stateName := parseStatusTo(args[1:]); stateName != "" {
+				if isatty.IsTerminal(os.Stdin.Fd()) {
+					fmt.Fprintln(os.Stderr, errorSymbol+" status --to reads identifiers from stdin; pipe them in")
+					os.Exit(1)
+				}
+				runBulkStatus(ctx, getLinearAuthHeader(ctx), os.Stdin, stateName)
+				return
+			}
+			if len(args) < 3 {
+				printStatusUsage()
+				return
+			}
+			runStatus(ctx, getLinearAuthHeader(ctx), args[1], strings.Join(args[2:], " "))
+		case "describe-team":
+			if hasHelpArg(args[1:]) {
+				printDescribeTeamUsage()
+				return
+			}
+			teamName, jsonOutput := parseIssueArgs(args[1:])
+			runDescribeTeam(ctx, getLinearAuthHeader(ctx), teamName, jsonOutput || *jsonOutputFlag)
+		case "auth":
+			runAuth(ctx, args[1:])
+		case "history":
+			if hasHelpArg(args[1:]) {
+				printHistoryUsage()
+				return
+			}
+			runHistory(hasJSONArg(args[1:]) || *jsonOutputFlag)
+		case "cache":
+			runCache(args[1:], *jsonOutputFlag, *dryRunFlag)
+		case "flush":
+			if hasHelpArg(args[1:]) {
+				printFlushUsage()
+				return
+			}
+			if offlineMode {
+				fmt.Fprintln(os.Stderr, errorSymbol+" flush needs a connection; drop --offline")
+				os.Exit(1)
+			}
+			runFlush(ctx, getLinearAuthHeader(ctx))
+		case "configure":
+			runConfigure(ctx, getLinearAuthHeader(ctx), estimateType)
+		case "completion":
+			if len(args) < 2 || hasHelpArg(args[1:]) {
+				printCompletionUsage()
+				return
+			}
+			runCompletion(args[1])
+		case "set-team":
+			runSetTeam(ctx, getLinearAuthHeader(ctx))
+		case "set-labels":
+			runSetLabels(ctx, getLinearAuthHeader(ctx))
+		case "set-estimate":
+			// Resolves the workspace whose defaults to update
+			getLinearAuthHeader(ctx)
+			runSetEstimate(estimateType)
+		case "set-status":
+			runSetStatus(ctx, getLinearAuthHeader(ctx))
+		case "reset":
+			runReset(*dryRunFlag || slices.Contains(args[1:], "--dry-run"))
+		case "help", "-h", "--help":
+			flag.Usage()
+		default:
+			fmt.Printf("Unknown command: %s\n\n", args[0])
+			flag.Usage()
+			os.Exit(1)
+		}
+		return
+	}
+
+	runCreate(ctx, createOptions)
+}
+
+// selectTeam returns the team for teamId, prompting when it's unset or no
+// longer exists. The most used teams are listed first.
+func selectTeam(teams []Team, teamId string, usage map[string]Usage) *Team {
+	if team := findTeam(teams, teamId); team != nil {
+		return team
+	}
+
+	options := teamOptions(teams)
+	sortOptionsByUsage(options, usage)
+	selectedTeamId := ""
+	teamForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Team").
+				Description("Select the team for this ticket").
+				Options(options...).
+				Filtering(true).
+				Value(&selectedTeamId),
+		),
+	)
+	if err := teamForm.Run(); err != nil {
+		exitOnFormError(err, "Team selection")
+	}
+
+	team := findTeam(teams, selectedTeamId)
+	if team == nil {
+		fmt.Fprintln(os.Stderr, errorSymbol+" Selected team not found")
+		os.Exit(1)
+	}
+
+	return team
+}
+
+// teamLabel is a label offered by --team-all with the teams that have it.
+// Workspace labels share one id across every team.
+type teamLabel struct {
+	Label Label
+	Teams []Team
+}
+
+// labelsAcrossTeams merges each team's labels by id, so a workspace label is
+// listed once while same-named team labels stay separate, sorted by name and
+// then team.
+func labelsAcrossTeams(teams []Team, labelsByTeam map[string][]Label) []teamLabel {
+	var merged []teamLabel
+	indexByID := make(map[string]int)
+	for _, team := range teams {
+		for _, label := range labelsByTeam[team.ID] {
+			if label.IsGroup {
+				continue
+			}
+			if i, ok := indexByID[label.ID]; ok {
+				merged[i].Teams = append(merged[i].Teams, team)
+				continue
+			}
+			indexByID[label.ID] = len(merged)
+			merged = append(merged, teamLabel{Label: label, Teams: []Team{team}})
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		a, b := merged[i], merged[j]
+		if !strings.EqualFold(a.Label.Name, b.Label.Name) {
+			return strings.ToLower(a.Label.Name) < strings.ToLower(b.Label.Name)
+		}
+		return a.Teams[0].Key < b.Teams[0].Key
+	})
+
+	return merged
+}
+
+// teamLabelOptionKey shows which teams have the label, e.g. "Bug (ENG, WEB)".
+func teamLabelOptionKey(label teamLabel) string {
+	keys := make([]string, len(label.Teams))
+	for i, team := range label.Teams {
+		keys[i] = team.Key
+	}
+
+	return labelOptionKey(label.Label) + " (" + strings.Join(keys, ", ") + ")"
+}
+
+// selectLabelAcrossTeams lets --team-all pick a label from every team. The
+// label's team becomes the ticket's team; a label several teams share asks
+// which of them to file in.
+func selectLabelAcrossTeams(ctx context.Context, apiKey string, teams []Team) (*Team, Label) {
+	labelsByTeam, err := withSpinner("Loading labels for all teams"+ellipsisSymbol, func() (map[string][]Label, error) {
+		labelsByTeam := make(map[string][]Label, len(teams))
+		for _, team := range teams {
+			labels, err := loadTeamLabels(ctx, apiKey, team.ID)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", team.Name, err)
+			}
+			labelsByTeam[team.ID] = labels
+		}
+		return labelsByTeam, nil
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching labels: %v\n", err)
+		os.Exit(1)
+	}
+
+	merged := labelsAcrossTeams(teams, labelsByTeam)
+	if len(merged) == 0 {
+		fmt.Fprintln(os.Stderr, errorSymbol+" No labels configured in any team")
+		os.Exit(1)
+	}
+
+	options := make([]huh.Option[string], len(merged))
+	for i, label := range merged {
+		options[i] = huh.Option[string]{Key: teamLabelOptionKey(label), Value: strconv.Itoa(i)}
+	}
+	selected := ""
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Label").
+				Description("Pick a label; its team is where the ticket is filed").
+				Options(options...).
+				Filtering(true).
+				Value(&selected),
+		),
+	)
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Label selection")
+	}
+
+	index, _ := strconv.Atoi(selected)
+	label := merged[index]
+	if len(label.Teams) == 1 {
+		return &label.Teams[0], label.Label
+	}
+
+	return selectTeam(label.Teams, "", nil), label.Label
+}
+
+type teamFormData struct {
+	Team            Team
+	Labels          []Label
+	Users           []User
+	WorkflowStates  []WorkflowState
+	EstimateType    int
+	DefaultEstimate string
+	LabelUsage      map[string]Usage
+	// Templates are the team's Linear issue templates (API key only)
+	Templates []LinearTemplate
+	// Projects are the team's open projects (API key only); milestones are
+	// loaded once a project is picked
+	Projects       []Project
+	LoadMilestones func(projectId string) []ProjectMilestone
+	// SkipSubscribers hides the Subscribers picker for OAuth sessions, whose
+	// save_issue tool can't add subscribers
+	SkipSubscribers bool
+}
+
+func loadTeamFormData(ctx context.Context, apiKey string, team Team, options CreateOptions) teamFormData {
+	data := teamFormData{Team: team, EstimateType: options.EstimateType}
+
+	labels, err := withSpinner("Loading labels"+ellipsisSymbol, func() ([]Label, error) {
+		return loadTeamLabels(ctx, apiKey, team.ID)
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching labels: %v\n", err)
+		os.Exit(1)
+	}
+	if options.CreateMissingLabels {
+		if labels, err = createMissingLabels(ctx, apiKey, team.ID, labels, options.Labels); err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+	}
+	data.Labels = labels
+
+	users, err := withSpinner("Loading users"+ellipsisSymbol, func() ([]User, error) {
+		return loadTeamUsers(ctx, apiKey, team.ID)
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching users: %v\n", err)
+		os.Exit(1)
+	}
+	if !options.IncludeInactive {
+		users = activeUsers(users)
+	}
+	data.Users = users
+
+	workflowStates, err := withSpinner("Loading workflow states"+ellipsisSymbol, func() ([]WorkflowState, error) {
+		return loadWorkflowStates(ctx, apiKey, team.ID)
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching workflow states: %v\n", err)
+		os.Exit(1)
+	}
+	data.WorkflowStates = workflowStates
+
+	if !options.EstimateTypeSet {
+		data.EstimateType = detectEstimateType(ctx, apiKey, team.ID, options.EstimateType)
+	}
+	data.DefaultEstimate = teamDefaultEstimate(ctx, apiKey, team.ID, data.EstimateType)
+
+	// Templates and projects are optional; OAuth sessions and API errors
+	// just skip them
+	_, data.SkipSubscribers = splitMCPAuthHeader(apiKey)
+	if !data.SkipSubscribers {
+		data.Templates, _ = loadTeamTemplates(ctx, apiKey, team.ID)
+		data.Projects, _ = loadTeamProjects(ctx, apiKey, team.ID)
+		data.LoadMilestones = func(projectId string) []ProjectMilestone {
+			milestones, _ := loadProjectMilestones(ctx, apiKey, projectId)
+			return milestones
+		}
+	}
+
+	return data
+}
+
+// newTicket builds a ticket pre-filled from cached selections, flags, the
+// template, and the team's description snippet.
+func newTicket(selections UserSelections, data teamFormData, ticketTemplate *TicketTemplate, options CreateOptions) LinearTicket {
+	ticket := LinearTicket{
+		TeamId:        data.Team.ID,
+		Estimate:      selections.Estimate,
+		Labels:        selections.Labels,
+		AssigneeId:    selections.AssigneeId,
+		SubscriberIds: selections.SubscriberIds,
+		StatusId:      selections.StatusId,
+		Fields:        options.Fields,
+		Position:      options.Position,
+		Images:        options.Images,
+		ParentId:      options.ParentId,
+	}
+	if options.EstimateRaw != "" {
+		ticket.Estimate = options.EstimateRaw
+	} else if options.Estimate == autoEstimate {
+		ticket.Estimate = data.DefaultEstimate
+	} else if options.Estimate != "" {
+		estimate, err := resolveEstimate(data.EstimateType, options.Estimate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid estimate for this team: %v\n", err)
+			os.Exit(1)
+		}
+		ticket.Estimate = estimate
+	} else if !hasOptionValue(getEstimateOptions(data.EstimateType), ticket.Estimate) {
+		// The team's estimate scale changed since this was cached
+		ticket.Estimate = ""
+	}
+	if ticket.Estimate == "" {
+		ticket.Estimate = data.DefaultEstimate
+	}
+
+	// Pre-fill fields from --description-file
+	if options.Title != "" {
+		ticket.Title = options.Title
+	}
+	if options.Description != "" {
+		ticket.Description = options.Description
+	}
+	if len(options.Labels) > 0 {
+		_, labelMap := labelOptions(data.Labels)
+		resolved, err := optionLabels(options.Labels, labelMap, data.Team.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		ticket.Labels = resolved
+	}
+	config := loadConfig()
+	assigneeId, err := resolveAssignee(data.Users, ticket.AssigneeId, config.defaultAssignee(data.Team), options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Invalid assignee for %s: %v\n", data.Team.Name, err)
+		os.Exit(1)
+	}
+	ticket.AssigneeId = assigneeId
+	if data.SkipSubscribers {
+		warnSkippedDefaultSubscribers(config.defaultSubscribers(data.Team), options)
+		ticket.SubscriberIds = nil
+	} else {
+		subscriberIds, err := resolveSubscribers(data.Users, ticket.SubscriberIds, config.defaultSubscribers(data.Team), options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid subscribers for %s: %v\n", data.Team.Name, err)
+			os.Exit(1)
+		}
+		ticket.SubscriberIds = subscriberIds
+	}
+	if options.StatusType != "" {
+		statusId, err := statusFromType(data.WorkflowStates, options.StatusType, data.Team.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+			os.Exit(1)
+		}
+		ticket.StatusId = statusId
+	}
+
+	// Pre-fill the title from the current git branch
+	if options.TitleFromBranch {
+		branch, err := currentGitBranch()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error reading current git branch: %v\n", err)
+			os.Exit(1)
+		}
+		ticket.Title = titleFromBranch(branch)
+	}
+
+	// Pre-fill fields from the selected template
+	if ticketTemplate != nil {
+		if err := applyTicketTemplate(&ticket, *ticketTemplate, newTemplateData()); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error rendering template: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if options.TemplateID != "" {
+		template, err := findLinearTemplate(data.Templates, options.TemplateID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Invalid --template-id for %s: %v\n", data.Team.Name, err)
+			os.Exit(1)
+		}
+		ticket.TemplateId = template.ID
+	}
+
+	// A description scaffold replaces only the description
+	if options.DescriptionTemplate != "" {
+		ticket.Description = options.DescriptionTemplate
+	}
+
+	ticket.Labels = enforceExclusiveLabels(ticket.Labels, data.Labels)
+
+	// Offer the team's saved description snippet
+	if snippet := selections.DescriptionSnippets[data.Team.ID]; snippet != "" && ticket.Description == "" {
+		useSnippet := true
+		snippetForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title("Prefill description from saved snippet?").
+					Description(fmt.Sprintf("Saved for %s", data.Team.Name)).
+					Value(&useSnippet),
+			),
+		)
+		if err := snippetForm.Run(); err != nil {
+			exitOnFormError(err, "Form")
+		}
+		if useSnippet {
+			ticket.Description = snippet
+		}
+	}
+
+	return ticket
+}
+
+// collapseTitle joins a multi-line (e.g. accidentally pasted) title into
+// one line, collapsing newlines, tabs, and repeated spaces to single spaces.
+func collapseTitle(title string) string {
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// fitTitle collapses the title to one line and enforces Linear's title
+// limit. Over-long titles are rejected, or cut to the limit with a warning
+// when truncate is set.
+func fitTitle(title string, truncate bool) (string, error) {
+	title = collapseTitle(title)
+	length := utf8.RuneCountInString(title)
+	if length <= maxTitleLength {
+		return title, nil
+	}
+	if !truncate {
+		return "", fmt.Errorf("title is %d characters; Linear allows at most %d (pass --truncate-title to cut it)", length, maxTitleLength)
+	}
+
+	fmt.Fprintf(os.Stderr, infoSymbol+" Title truncated from %d to %d characters\n", length, maxTitleLength)
+	return strings.TrimSpace(string([]rune(title)[:maxTitleLength])), nil
+}
+
+// validateTitle returns the title input's validator: the title can't be empty
+// or, without --truncate-title, longer than Linear allows.
+func validateTitle(options CreateOptions) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+		if length := utf8.RuneCountInString(collapseTitle(s)); length > maxTitleLength && !options.TruncateTitle {
+			return fmt.Errorf("title is %d characters; Linear allows at most %d", length, maxTitleLength)
+		}
+		return nil
+	}
+}
+
+func runTicketForm(ticket *LinearTicket, data teamFormData, options CreateOptions) error {
+	fields := []huh.Field{
+		huh.NewInput().
+			Title("Ticket Title").
+			Description("A brief summary of the issue or feature").
+			Value(&ticket.Title).
+			Validate(validateTitle(options)),
+
+		huh.NewText().
+			Title("Description").
+			Description("Detailed description of the ticket").
+			Value(&ticket.Description).
+			Lines(5),
+	}
+
+	if !options.Minimal {
+		fields = append(fields, ticketDetailFields(ticket, data, options)...)
+	}
+
+	groups := []*huh.Group{huh.NewGroup(fields...)}
+	if len(data.Projects) > 0 && data.LoadMilestones != nil && !options.Minimal {
+		// Shown only once a project is picked, with that project's milestones
+		groups = append(groups, huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Milestone").
+				Description("Select the project milestone for this ticket").
+				OptionsFunc(func() []huh.Option[string] {
+					options := []huh.Option[string]{huh.NewOption("No milestone", "")}
+					for _, milestone := range data.LoadMilestones(ticket.ProjectId) {
+						options = append(options, huh.NewOption(milestone.Name, milestone.ID))
+					}
+					return options
+				}, &ticket.ProjectId).
+				Value(&ticket.ProjectMilestoneId),
+		).WithHideFunc(func() bool {
+			return ticket.ProjectId == ""
+		}))
+	}
+
+	if err := huh.NewForm(groups...).Run(); err != nil {
+		return err
+	}
+	if ticket.ProjectId == "" {
+		ticket.ProjectMilestoneId = ""
+	}
+
+	title, err := fitTitle(ticket.Title, options.TruncateTitle)
+	if err != nil {
+		return err
+	}
+	ticket.Title = title
+	return nil
+}
+
+// assigneeOptions lists "No assignee", then the viewer as "Me" when they're
+// in users, then everyone else.
+func assigneeOptions(users []User) []huh.Option[string] {
+	options := []huh.Option[string]{{Key: "No assignee", Value: ""}}
+	var others []huh.Option[string]
+	for _, user := range users {
+		if viewer.ID != "" && user.ID == viewer.ID {
+			options = append(options, huh.Option[string]{Key: "Me (" + user.Name + ")", Value: user.ID})
+			continue
+		}
+		others = append(others, huh.Option[string]{Key: user.Name, Value: user.ID})
+	}
+
+	return append(options, others...)
+}
+
+// ticketDetailFields builds the pickers after the title and description,
+// skipping those the team has nothing configured for. --minimal leaves them
+// out so the saved choices are used as is.
+func ticketDetailFields(ticket *LinearTicket, data teamFormData, options CreateOptions) []huh.Field {
+	estimateOptions := estimatePickerOptions(data.EstimateType)
+	labelOptions, _ := labelOptions(data.Labels)
+	sortOptionsByUsage(labelOptions, data.LabelUsage)
+
+	userOptions := assigneeOptions(data.Users)
+
+	statusOptions := make([]huh.Option[string], len(data.WorkflowStates))
+	for i, state := range data.WorkflowStates {
+		statusOptions[i] = huh.Option[string]{Key: state.Name, Value: state.ID}
+	}
+
+	var fields []huh.Field
+	if len(data.Templates) > 0 {
+		templateOptions := []huh.Option[string]{huh.NewOption("None", "")}
+		for _, template := range data.Templates {
+			templateOptions = append(templateOptions, huh.NewOption(template.Name, template.ID))
+		}
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Linear Template").
+			Description("Apply one of the team's templates from Linear; the fields you set here win").
+			Options(templateOptions...).
+			Value(&ticket.TemplateId))
+	}
+
+	if len(statusOptions) > 0 {
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Status").
+			Description("Select the status for this ticket").
+			Options(statusOptions...).
+			Value(&ticket.StatusId))
+	} else {
+		infof("No workflow states configured for %s\n", data.Team.Name)
+	}
+
+	if len(data.Projects) > 0 {
+		projectOptions := []huh.Option[string]{huh.NewOption("No project", "")}
+		for _, project := range data.Projects {
+			projectOptions = append(projectOptions, huh.NewOption(project.Name, project.ID))
+		}
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Project").
+			Description("Add the ticket to one of the team's projects").
+			Options(projectOptions...).
+			Filtering(true).
+			Value(&ticket.ProjectId))
+	}
+
+	// An --estimate-raw value isn't on the scale, so the select would replace it
+	if options.EstimateRaw == "" {
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Estimate").
+			Description("Story point estimate").
+			Options(estimateOptions...).
+			Value(&ticket.Estimate))
+	}
+
+	fields = append(fields,
+		huh.NewSelect[string]().
+			Title("Priority").
+			Description("Select the priority for this ticket").
+			Options(getPriorityOptions()...).
+			Value(&ticket.Priority),
+	)
+
+	if len(labelOptions) > 0 {
+		labels := huh.NewMultiSelect[string]().
+			Title("Labels").
+			Description("Select applicable labels (/ to filter, space to toggle, enter to confirm)")
+		groupOptions := labelGroupOptions(data.Labels)
+		if len(labelOptions) > labelGroupPickerThreshold && len(groupOptions) > 0 {
+			labelGroup := allLabelGroups
+			fields = append(fields, huh.NewSelect[string]().
+				Title("Label Group").
+				Description("Narrow the label list to one group, or pick All").
+				Options(groupOptions...).
+				Value(&labelGroup))
+			labels = labels.OptionsFunc(func() []huh.Option[string] {
+				return labelsInGroup(labelOptions, data.Labels, labelGroup, ticket.Labels)
+			}, &labelGroup)
+		} else {
+			labels = labels.Options(labelOptions...)
+		}
+		fields = append(fields, labels.
+			Filtering(true).
+			Value(&ticket.Labels).
+			Validate(func(selected []string) error {
+				return exclusiveLabelConflict(selected, data.Labels)
+			}).
+			Limit(4))
+	} else {
+		infof("No labels configured for %s\n", data.Team.Name)
+	}
+
+	if len(data.Users) > 0 {
+		fields = append(fields,
+			huh.NewSelect[string]().
+				Title("Assignee").
+				Description("Select who should work on this ticket").
+				Options(userOptions...).
+				Filtering(true).
+				Value(&ticket.AssigneeId),
+		)
+		if !data.SkipSubscribers {
+			fields = append(fields,
+				huh.NewMultiSelect[string]().
+					Title("Subscribers").
+					Description("Select teammates to keep in the loop (space to toggle, enter to confirm)").
+					Options(subscriberOptions(data.Users)...).
+					Filtering(true).
+					Value(&ticket.SubscriberIds),
+			)
+		}
+	} else {
+		infof("No users available to assign in %s\n", data.Team.Name)
+	}
+
+	return fields
+}
+
+// confirmTicket asks whether to submit the ticket, letting the user preview
+// the description as rendered markdown or go back and edit the form first.
+func confirmTicket(ticket *LinearTicket, data teamFormData, options CreateOptions) error {
+	for {
+		choice := "create"
+		confirmForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("Create this ticket?").
+					Options(
+						huh.NewOption("Create ticket", "create"),
+						huh.NewOption("Preview description", "preview"),
+						huh.NewOption("Edit ticket", "edit"),
+					).
+					Value(&choice),
+			),
+		)
+		if err := confirmForm.Run(); err != nil {
+			return err
+		}
+
+		switch choice {
+		case "preview":
+			fmt.Println("\n" + ruleLine)
+			fmt.Println(renderMarkdown(ticket.Description))
+			fmt.Println(ruleLine)
+		case "edit":
+			if err := runTicketForm(ticket, data, options); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func runCreate(ctx context.Context, options CreateOptions) {
+	requireTerminal("use 'lnr quick <title>' or --import for non-interactive mode")
+
+	var ticketTemplate *TicketTemplate
+	if options.Template != "" {
+		templates, err := loadTicketTemplates()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error loading templates: %v\n", err)
+			os.Exit(1)
+		}
+		tmpl, found := templates[options.Template]
+		if !found {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Template not found: %s\n", options.Template)
+			os.Exit(1)
+		}
+		ticketTemplate = &tmpl
+	}
+
+	// Get API credentials, then the defaults saved for their workspace
+	apiKey := getLinearAuthHeader(ctx)
+	selections := loadUserSelections()
+
+	// Fetch teams
+	teams, err := withSpinner("Loading teams"+ellipsisSymbol, func() ([]Team, error) {
+		return loadTeams(ctx, apiKey)
+	})
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching teams: %v\n", err)
+		os.Exit(1)
+	}
+
+	resolveParentAuto(ctx, apiKey, &options)
+
+	// --team-all picks a label first and files in the team that owns it
+	if options.TeamAll {
+		team, label := selectLabelAcrossTeams(ctx, apiKey, teams)
+		selections.switchTeam(team.ID)
+		selections.Labels = []string{label.Name}
+	}
+
+	// The repo's team, then a --team flag, take precedence over the cached team
+	if team := repoDefaultTeam(teams, selections); team != nil && !options.TeamAll {
+		selections.switchTeam(team.ID)
+	}
+	if options.TeamName != "" {
+		team, err := resolveTeam(ctx, apiKey, options.TeamName)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error resolving team: %v\n", err)
+			os.Exit(1)
+		}
+		if findTeam(teams, team.ID) == nil {
+			teams = append(teams, *team)
+		}
+		selections.switchTeam(team.ID)
+	}
+
+	// Select team - pre-select from cache and skip if already cached
+	selectedTeam := selectTeam(teams, selections.TeamId, selections.TeamUsage)
+	selections.switchTeam(selectedTeam.ID)
+	*selectedTeam = refreshTeam(ctx, apiKey, teams, *selectedTeam)
+
+	// Fetch team labels, users, and workflow states once; "Create another"
+	// reuses them
+	data := loadTeamFormData(ctx, apiKey, *selectedTeam, options)
+	_, labelMap := labelOptions(data.Labels)
+
+	// Prefetch the other teams while the form is open so switching is instant
+	if loadConfig().WarmCache && !offlineMode && len(teams) > 1 {
+		go warmTeamCaches(ctx, apiKey, teams, selectedTeam.ID)
+	}
+
+	// retryTicket holds what was entered when creating failed, so the form
+	// reopens with it instead of starting over
+	var retryTicket *LinearTicket
+	for {
+		var ticket LinearTicket
+		if retryTicket != nil {
+			ticket, retryTicket = *retryTicket, nil
+		} else {
+			ticket = newTicket(selections, data, ticketTemplate, options)
+		}
+
+		// Run the form
+		data.LabelUsage = selections.LabelUsage
+		if err := runTicketForm(&ticket, data, options); err != nil {
+			exitOnFormError(err, "Form")
+		}
+
+		// Display the collected information
+		if !quietOutput {
+			printTicketSummary(ticket, data.Team, data.EstimateType, data.WorkflowStates, data.Users)
+		}
+
+		// --preview adds a confirmation step that can render the description
+		if options.Preview && strings.TrimSpace(ticket.Description) != "" {
+			if err := confirmTicket(&ticket, data, options); err != nil {
+				exitOnFormError(err, "Form")
+			}
+		}
+
+		// The key and images belong to the first ticket only; "Create another"
+		// makes new ones
+		applyIdempotencyKey(apiKey, &ticket, options)
+		options.IdempotencyKey = ""
+		options.Images = nil
+
+		if offlineMode {
+			queueTicketOrExit(ticket, labelMap, options)
+			saveTicketSelections(&selections, ticket, options)
+
+			queueAnother := false
+			confirmForm := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title("Queue another ticket?").
+						Value(&queueAnother),
+				),
+			)
+			if err := confirmForm.Run(); err != nil {
+				exitOnFormError(err, "Form")
+			}
+			if !queueAnother {
 				return
 			}
-			searchTerm, jsonOutput := parseIssueArgs(args[1:])
-			runIssueSearch(getLinearAuthHeader(), searchTerm, jsonOutput || *jsonOutputFlag)
-		case "auth":
-			runAuth(args[1:])
-		case "configure":
-			runConfigure(getLinearAuthHeader())
-		case "completion":
-			if len(args) < 2 || hasHelpArg(args[1:]) {
-				printCompletionUsage()
-				return
+			continue
+		}
+
+		if !quietOutput {
+			fmt.Println("\n" + createSymbol + " Creating ticket in Linear...")
+		}
+		issue, err := createLinearTicket(ctx, apiKey, ticket, labelMap)
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Error creating ticket: %v\n", err)
+			if !confirmEditAfterError() {
+				os.Exit(1)
+			}
+			retryTicket = &ticket
+			continue
+		}
+
+		if quietOutput {
+			fmt.Println(issue.Identifier)
+		} else {
+			fmt.Printf(successSymbol+" Ticket created successfully! ID: %s\n", issue.Identifier)
+		}
+		recordHistory(issue, ticket.TeamId)
+		writeOutputFile(options, issue)
+		attachLinkFromOptions(ctx, apiKey, issue, options)
+		relateIssuesFromOptions(ctx, apiKey, issue, options)
+		options.clearFirstTicketOnly()
+
+		saveTicketSelections(&selections, ticket, options)
+
+		if options.CopyBranch {
+			copyToClipboard("Branch name", fallbackBranchName(issue))
+		}
+		if options.Slack {
+			copyToClipboard("Slack message", slackMessage(issue))
+		}
+		if options.Open {
+			openIssue(issue)
+		}
+		if skipsPostCreateMenu(options) {
+			return
+		}
+		switch runPostCreateMenu(ctx, apiKey, issue, len(teams) > 1) {
+		case "another":
+		case "switch":
+			// Fetch the new team's labels, users, and states and use the
+			// choices last made for it
+			selectedTeam = selectTeam(teams, "", selections.TeamUsage)
+			selections.switchTeam(selectedTeam.ID)
+			*selectedTeam = refreshTeam(ctx, apiKey, teams, *selectedTeam)
+			data = loadTeamFormData(ctx, apiKey, *selectedTeam, options)
+			_, labelMap = labelOptions(data.Labels)
+		default:
+			return
+		}
+	}
+}
+
+// confirmEditAfterError asks whether to reopen the form to fix a ticket Linear
+// rejected. Without a terminal there's no one to ask, so it's always no.
+func confirmEditAfterError() bool {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return false
+	}
+
+	edit := true
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Edit the ticket and try again?").
+				Description("The form reopens with everything you entered").
+				Affirmative("Edit").
+				Negative("Quit").
+				Value(&edit),
+		),
+	)
+	if err := form.Run(); err != nil {
+		exitOnFormError(err, "Form")
+	}
+
+	return edit
+}
+
+// writeOutputFile records created issues in --output-file for CI steps:
+// "identifier url" lines (plain), or JSON (an object for one issue, an
+// array for several).
+func writeOutputFile(options CreateOptions, issues ...CreatedIssue) {
+	if options.OutputFile == "" || len(issues) == 0 {
+		return
+	}
+
+	var data []byte
+	if options.OutputFormat == "json" {
+		var err error
+		if len(issues) == 1 {
+			data, err = json.Marshal(issues[0])
+		} else {
+			data, err = json.Marshal(issues)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to encode JSON: %v\n", err)
+			os.Exit(1)
+		}
+		data = append(data, '\n')
+	} else {
+		var lines strings.Builder
+		for _, issue := range issues {
+			fmt.Fprintf(&lines, "%s %s\n", issue.Identifier, issue.URL)
+		}
+		data = []byte(lines.String())
+	}
+
+	if err := writeFileAtomic(options.OutputFile, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error writing %s: %v\n", options.OutputFile, err)
+		os.Exit(1)
+	}
+}
+
+// copyToClipboard copies text to the clipboard. When stdout isn't a terminal
+// (CI, SSH, command substitution) it prints text instead, and when there's no
+// clipboard it shows text under label to copy by hand. With --quiet it only
+// copies, leaving stdout to the identifier, and reports failures on stderr.
+func copyToClipboard(label, text string) {
+	if quietOutput {
+		if err := clipboard.WriteAll(text); err != nil {
+			fmt.Fprintln(os.Stderr, errorSymbol+" "+clipboardErrorMessage(err))
+		}
+		return
+	}
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		fmt.Println(text)
+		return
+	}
+
+	if err := clipboard.WriteAll(text); err != nil {
+		fmt.Fprintln(os.Stderr, errorSymbol+" "+clipboardErrorMessage(err))
+		fmt.Printf("\n%s (copy it manually):\n\n  %s\n\n", label, text)
+		return
+	}
+	fmt.Printf(copySymbol+" Copied '%s' to clipboard\n", text)
+}
+
+// slackMessage formats the issue as a Slack link, <url|ENG-12: Title>,
+// escaping the characters Slack reserves in message text.
+func slackMessage(issue CreatedIssue) string {
+	escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return "<" + issueURL(issue) + "|" + escaper.Replace(issue.Identifier+": "+issue.Title) + ">"
+}
+
+// clipboardDescription reads the clipboard for --description-from-clipboard.
+// An unreadable clipboard only warns, so the description can still be
+// written in the form; an empty one is an error since nothing was copied.
+func clipboardDescription() (string, error) {
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, infoSymbol+" Couldn't read the clipboard (%v); continuing without a description\n", err)
+		return "", nil
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("the clipboard is empty; copy the description first")
+	}
+
+	return strings.TrimRight(text, "\r\n"), nil
+}
+
+// clipboardErrorMessage explains a failed clipboard write, suggesting a
+// clipboard utility when none is installed (common on headless Linux and SSH).
+func clipboardErrorMessage(err error) string {
+	if strings.Contains(err.Error(), "No clipboard utilities available") {
+		return "No clipboard utility found; install xclip or xsel (or wl-clipboard on Wayland) to enable copying"
+	}
+
+	return fmt.Sprintf("Failed to copy to clipboard: %v", err)
+}
+
+func issueURL(issue CreatedIssue) string {
+	if issue.URL != "" {
+		return issue.URL
+	}
+	if viewer.URLKey != "" {
+		return fmt.Sprintf("https://linear.app/%s/issue/%s", viewer.URLKey, issue.Identifier)
+	}
+
+	return fmt.Sprintf("https://linear.app/issue/%s", issue.Identifier)
+}
+
+// openIssue opens the issue in the default browser, reporting failures
+// without exiting.
+func openIssue(issue CreatedIssue) {
+	url := issueURL(issue)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "linux":
+		cmd = exec.Command("xdg-open", url)
+	}
+	if cmd != nil {
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to open URL: %v\n", err)
+		}
+	}
+}
+
+// saveTicketSelections remembers the ticket's choices as the next defaults.
+func saveTicketSelections(selections *UserSelections, ticket LinearTicket, options CreateOptions) {
+	selections.TeamId = ticket.TeamId
+	selections.AssigneeId = ticket.AssigneeId
+	selections.SubscriberIds = ticket.SubscriberIds
+	selections.Labels = ticket.Labels
+	selections.Estimate = ticket.Estimate
+	selections.StatusId = ticket.StatusId
+	selections.TeamUsage = recordUsage(selections.TeamUsage, ticket.TeamId)
+	for _, label := range ticket.Labels {
+		selections.LabelUsage = recordUsage(selections.LabelUsage, label)
+	}
+	if repo := currentRepoKey(); repo != "" {
+		if selections.RepoTeams == nil {
+			selections.RepoTeams = make(map[string]string)
+		}
+		selections.RepoTeams[repo] = ticket.TeamId
+	}
+	if options.SaveSnippet && ticket.Description != "" {
+		if selections.DescriptionSnippets == nil {
+			selections.DescriptionSnippets = make(map[string]string)
+		}
+		selections.DescriptionSnippets[ticket.TeamId] = ticket.Description
+	}
+	saveUserSelections(*selections)
+}
+
+// skipsPostCreateMenu reports whether flags already decided what happens
+// after creating, so the menu (which waits for input) isn't shown.
+func skipsPostCreateMenu(options CreateOptions) bool {
+	return options.CopyBranch || options.Slack || options.Open || options.NoPostMenu
+}
+
+// runPostCreateMenu shows the post-creation menu and returns the chosen action.
+func runPostCreateMenu(ctx context.Context, apiKey string, issue CreatedIssue, canSwitchTeam bool) string {
+	options := []huh.Option[string]{
+		{Key: "Copy branch name", Value: "branch"},
+		{Key: "Copy issue URL", Value: "url"},
+		{Key: "Copy Slack message", Value: "slack"},
+		{Key: "Open in Linear", Value: "open"},
+		{Key: "Attach a link", Value: "attach"},
+		{Key: "Create another ticket", Value: "another"},
+	}
+	if canSwitchTeam {
+		options = append(options, huh.Option[string]{Key: "Switch team and create another", Value: "switch"})
+	}
+	options = append(options, huh.Option[string]{Key: "Exit", Value: "exit"})
+
+	var action string
+	postForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("What would you like to do?").
+				Options(options...).
+				Value(&action),
+		),
+	)
+
+	if err := postForm.Run(); err != nil {
+		exitOnFormError(err, "Menu")
+	}
+
+	switch action {
+	case "branch":
+		copyToClipboard("Branch name", fallbackBranchName(issue))
+	case "url":
+		copyToClipboard("Issue URL", issueURL(issue))
+	case "slack":
+		copyToClipboard("Slack message", slackMessage(issue))
+	case "open":
+		openIssue(issue)
+	case "attach":
+		var linkURL, linkTitle string
+		linkForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Link URL").
+					Value(&linkURL).
+					Validate(validateLinkURL),
+				huh.NewInput().
+					Title("Link Title").
+					Description("Defaults to the URL").
+					Value(&linkTitle),
+			),
+		)
+		if err := linkForm.Run(); err != nil {
+			exitOnFormError(err, "Menu")
+		}
+		if err := createAttachment(ctx, apiKey, issue.Identifier, linkURL, linkTitle); err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to attach link: %v\n", err)
+		} else {
+			fmt.Printf(linkSymbol+" Attached %s\n", linkURL)
+		}
+	case "exit":
+		// Do nothing, just exit
+	}
+
+	return action
+}
+
+func validateLinkURL(value string) error {
+	parsedURL, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return fmt.Errorf("enter a full URL, e.g. https://github.com/org/repo/pull/1")
+	}
+
+	return nil
+}
+
+func createAttachment(ctx context.Context, apiKey, issueId, linkURL, title string) error {
+	if err := requireAPIKey(apiKey, "attaching links"); err != nil {
+		return err
+	}
+	if err := validateLinkURL(linkURL); err != nil {
+		return err
+	}
+
+	linkURL = strings.TrimSpace(linkURL)
+	if strings.TrimSpace(title) == "" {
+		title = linkURL
+	}
+
+	mutation := `
+		mutation AttachmentCreate($input: AttachmentCreateInput!) {
+			attachmentCreate(input: $input) {
+				success
+			}
+		}
+	`
+
+	_, err := makeLinearRequest(ctx, apiKey, mutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"issueId": issueId,
+			"url":     linkURL,
+			"title":   title,
+		},
+	})
+	return err
+}
+
+// clearFirstTicketOnly drops the options that belong to the first ticket of a
+// session, so "Create another" and --watch don't attach the same link and
+// images or add the same relations to every ticket.
+func (options *CreateOptions) clearFirstTicketOnly() {
+	options.AttachURL = ""
+	options.AttachTitle = ""
+	options.Blocks = nil
+	options.BlockedBy = nil
+	options.Images = nil
+}
+
+// attachLinkFromOptions attaches the --attach link; failures are reported but
+// don't undo the created issue.
+func attachLinkFromOptions(ctx context.Context, apiKey string, issue CreatedIssue, options CreateOptions) {
+	if options.AttachURL == "" {
+		return
+	}
+
+	if err := createAttachment(ctx, apiKey, issue.Identifier, options.AttachURL, options.AttachTitle); err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Failed to attach link: %v\n", err)
+	}
+}
+
+var markdownImageRefPattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// validateImageFile checks that path is a regular file that both looks like an
+// image by extension and sniffs as one, so --attach-image can't be pointed at
+// an arbitrary file (a key, a config) and quietly publish it.
+func validateImageFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is not a file", path)
+	}
+	if !strings.HasPrefix(mime.TypeByExtension(strings.ToLower(filepath.Ext(path))), "image/") {
+		return fmt.Errorf("%s doesn't have an image extension", path)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if !strings.HasPrefix(http.DetectContentType(head[:n]), "image/") {
+		return fmt.Errorf("%s is not an image", path)
+	}
+
+	return nil
+}
+
+// uploadDescriptionImages uploads images (from --attach-image). A description
+// reference to one of them, such as ![crash](./crash.png), is rewritten to the
+// hosted URL; the rest are appended as new image references. Other local
+// references are left as is, so nothing is uploaded that wasn't asked for.
+func uploadDescriptionImages(ctx context.Context, apiKey, description string, images []string) (string, error) {
+	uploaded := make(map[string]string)
+	for _, path := range images {
+		if _, ok := uploaded[path]; ok {
+			continue
+		}
+		assetURL, err := uploadFile(ctx, apiKey, path)
+		if err != nil {
+			return "", fmt.Errorf("uploading %s: %w", path, err)
+		}
+		uploaded[path] = assetURL
+	}
+
+	referenced := make(map[string]bool)
+	description = markdownImageRefPattern.ReplaceAllStringFunc(description, func(match string) string {
+		parts := markdownImageRefPattern.FindStringSubmatch(match)
+		if assetURL, ok := uploaded[parts[2]]; ok {
+			referenced[parts[2]] = true
+			return "![" + parts[1] + "](" + assetURL + ")"
+		}
+		return match
+	})
+
+	for _, path := range images {
+		if referenced[path] {
+			continue
+		}
+		referenced[path] = true
+		if description != "" {
+			description = strings.TrimRight(description, "\n") + "\n\n"
+		}
+		description += "![" + filepath.Base(path) + "](" + uploaded[path] + ")"
+	}
+
+	return description, nil
+}
+
+// uploadFile stores a local file with Linear's file storage and returns its
+// hosted URL. Linear hands out a signed upload URL, and the file is PUT there.
+func uploadFile(ctx context.Context, apiKey, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	mutation := `
+		mutation FileUpload($contentType: String!, $filename: String!, $size: Int!) {
+			fileUpload(contentType: $contentType, filename: $filename, size: $size) {
+				success
+				uploadFile {
+					uploadUrl
+					assetUrl
+					headers {
+						key
+						value
+					}
+				}
 			}
-			runCompletion(args[1])
-		case "set-team":
-			runSetTeam(getLinearAuthHeader())
-		case "set-labels":
-			runSetLabels(getLinearAuthHeader())
-		case "set-estimate":
-			runSetEstimate()
-		case "set-status":
-			runSetStatus(getLinearAuthHeader())
-		case "reset":
-			if err := resetData(); err != nil {
-				fmt.Printf("❌ Error clearing data: %v\n", err)
-				os.Exit(1)
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, mutation, map[string]interface{}{
+		"contentType": contentType,
+		"filename":    filepath.Base(path),
+		"size":        len(content),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	fileUpload, _ := data["fileUpload"].(map[string]interface{})
+	uploadFile, _ := fileUpload["uploadFile"].(map[string]interface{})
+	uploadURL := getString(uploadFile, "uploadUrl")
+	assetURL := getString(uploadFile, "assetUrl")
+	if uploadURL == "" || assetURL == "" {
+		return "", fmt.Errorf("Linear didn't return an upload URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", uploadURL, bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", "public, max-age=31536000")
+	headers, _ := uploadFile["headers"].([]interface{})
+	for _, item := range headers {
+		if header, ok := item.(map[string]interface{}); ok {
+			req.Header.Set(getString(header, "key"), getString(header, "value"))
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("upload failed with status %d", resp.StatusCode)
+	}
+
+	return assetURL, nil
+}
+
+var issueIdentifierPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+$`)
+
+// repeatedFlag collects every value of a flag given more than once.
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseFields turns --field name=value pairs into issueCreate input fields.
+// Values that parse as JSON (numbers, booleans, null, objects, arrays, and
+// quoted strings) keep their type; anything else is a string.
+func parseFields(values []string) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	fields := make(map[string]interface{}, len(values))
+	for _, value := range values {
+		name, raw, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=value, got %q", value)
+		}
+
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			parsed = raw
+		}
+		fields[name] = parsed
+	}
+
+	return fields, nil
+}
+
+var branchIssuePattern = regexp.MustCompile(`([A-Za-z][A-Za-z0-9]*)-([0-9]+)`)
+
+// branchIssueIdentifier finds the issue identifier in a branch name such as
+// "ada/eng-123-fix-login", returning it upper-cased. Only prefixes that are a
+// team's key count, so "release-2024" isn't mistaken for an issue.
+func branchIssueIdentifier(branch string, teams []Team) string {
+	for _, match := range branchIssuePattern.FindAllStringSubmatch(branch, -1) {
+		for _, team := range teams {
+			if strings.EqualFold(match[1], team.Key) {
+				return strings.ToUpper(match[0])
 			}
-			fmt.Println("✅ Data cleared successfully")
-		case "help", "-h", "--help":
-			flag.Usage()
-		default:
-			fmt.Printf("Unknown command: %s\n\n", args[0])
-			flag.Usage()
-			os.Exit(1)
 		}
+	}
+
+	return ""
+}
+
+// resolveParentAuto sets options.ParentId to the issue named in the current
+// git branch when --parent-auto is given, exiting if there isn't one.
+func resolveParentAuto(ctx context.Context, apiKey string, options *CreateOptions) {
+	if !options.ParentAuto {
 		return
 	}
+	if err := requireAPIKey(apiKey, "--parent-auto"); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
+		os.Exit(1)
+	}
+
+	branch, err := currentGitBranch()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error reading current git branch: %v\n", err)
+		os.Exit(1)
+	}
+	teams, err := loadTeams(ctx, apiKey)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error fetching teams: %v\n", err)
+		os.Exit(1)
+	}
+	identifier := branchIssueIdentifier(branch, teams)
+	if identifier == "" {
+		fmt.Fprintf(os.Stderr, errorSymbol+" No issue identifier (e.g. ENG-123) in branch %q for --parent-auto\n", branch)
+		os.Exit(1)
+	}
+	ref, err := fetchIssueRef(ctx, apiKey, identifier)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error finding parent issue %s: %v\n", identifier, err)
+		os.Exit(1)
+	}
+
+	options.ParentId = ref.ID
+	infof("Creating a sub-issue of %s\n", identifier)
+}
+
+// parseIssueIdentifiers splits a comma-separated list like "ENG-1, eng-2"
+// into upper-cased identifiers.
+func parseIssueIdentifiers(value string) ([]string, error) {
+	var identifiers []string
+	for _, identifier := range strings.Split(value, ",") {
+		identifier = strings.TrimSpace(identifier)
+		if identifier == "" {
+			continue
+		}
+		if !issueIdentifierPattern.MatchString(identifier) {
+			return nil, fmt.Errorf("not an issue identifier: %s", identifier)
+		}
+		identifiers = append(identifiers, strings.ToUpper(identifier))
+	}
+
+	return identifiers, nil
+}
+
+// fetchCreatedIssue looks up an existing issue by id or identifier.
+func fetchCreatedIssue(ctx context.Context, apiKey, id string) (CreatedIssue, error) {
+	query := `
+		query CreatedIssue($id: String!) {
+			issue(id: $id) {
+				identifier
+				branchName
+				title
+				url
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return CreatedIssue{}, err
+	}
 
-	var ticket LinearTicket
-	selections := loadUserSelections()
+	data, _ := result["data"].(map[string]interface{})
+	issue, ok := data["issue"].(map[string]interface{})
+	if !ok || getString(issue, "identifier") == "" {
+		return CreatedIssue{}, fmt.Errorf("issue not found: %s", id)
+	}
 
-	// Get API credentials
-	apiKey := getLinearAuthHeader()
+	return CreatedIssue{
+		Identifier: getString(issue, "identifier"),
+		BranchName: getString(issue, "branchName"),
+		Title:      getString(issue, "title"),
+		URL:        getString(issue, "url"),
+	}, nil
+}
 
-	// Fetch teams
-	teams, err := loadTeams(apiKey)
+// fetchIssueRef resolves an identifier like ENG-123 to the issue's UUID and team.
+func fetchIssueRef(ctx context.Context, apiKey, identifier string) (IssueRef, error) {
+	query := `
+		query IssueRef($id: String!) {
+			issue(id: $id) {
+				id
+				team {
+					id
+				}
+			}
+		}
+	`
+
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"id": identifier})
 	if err != nil {
-		fmt.Printf("❌ Error fetching teams: %v\n", err)
-		os.Exit(1)
+		return IssueRef{}, err
 	}
 
-	// Create team selection options
-	teamOptions := teamOptions(teams)
+	data, _ := result["data"].(map[string]interface{})
+	issue, ok := data["issue"].(map[string]interface{})
+	if !ok || getString(issue, "id") == "" {
+		return IssueRef{}, fmt.Errorf("issue not found: %s", identifier)
+	}
 
-	// Select team - pre-select from cache and skip if already cached
-	var selectedTeamId string = selections.TeamId
-	if selectedTeamId == "" {
-		// No cached team, show selection
-		teamForm := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title("Team").
-					Description("Select the team for this ticket").
-					Options(teamOptions...).
-					Value(&selectedTeamId),
-			),
-		)
-		if err := teamForm.Run(); err != nil {
-			fmt.Println("Team selection cancelled or error:", err)
-			os.Exit(1)
-		}
-	} else {
-		// Team is cached, verify it still exists
-		teamExists := false
-		for _, team := range teams {
-			if team.ID == selectedTeamId {
-				teamExists = true
-				break
+	ref := IssueRef{ID: getString(issue, "id")}
+	if team, ok := issue["team"].(map[string]interface{}); ok {
+		ref.TeamID = getString(team, "id")
+	}
+
+	return ref, nil
+}
+
+func updateIssueState(ctx context.Context, apiKey, issueId, stateId string) error {
+	mutation := `
+		mutation IssueUpdate($id: String!, $input: IssueUpdateInput!) {
+			issueUpdate(id: $id, input: $input) {
+				success
 			}
 		}
-		if !teamExists {
-			// Cached team no longer exists, show selection
-			selectedTeamId = ""
-			teamForm := huh.NewForm(
-				huh.NewGroup(
-					huh.NewSelect[string]().
-						Title("Team").
-						Description("Select the team for this ticket").
-						Options(teamOptions...).
-						Value(&selectedTeamId),
-				),
-			)
-			if err := teamForm.Run(); err != nil {
-				fmt.Println("Team selection cancelled or error:", err)
-				os.Exit(1)
-			}
+	`
+
+	_, err := makeLinearRequest(ctx, apiKey, mutation, map[string]interface{}{
+		"id":    issueId,
+		"input": map[string]interface{}{"stateId": stateId},
+	})
+	return err
+}
+
+func findWorkflowState(states []WorkflowState, name string) *WorkflowState {
+	for _, state := range states {
+		if strings.EqualFold(state.Name, name) {
+			return &state
 		}
 	}
 
-	// Find selected team
-	var selectedTeam *Team
-	for _, team := range teams {
-		if team.ID == selectedTeamId {
-			selectedTeam = &team
-			break
+	return nil
+}
+
+func workflowStateNames(states []WorkflowState) []string {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = state.Name
+	}
+
+	return names
+}
+
+// workflowStateTypes are the state categories Linear shares across teams,
+// whatever the teams call their states.
+var workflowStateTypes = []string{"triage", "backlog", "unstarted", "started", "completed", "canceled"}
+
+func parseStatusType(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "cancelled" {
+		value = "canceled"
+	}
+	if !hasString(workflowStateTypes, value) {
+		return "", fmt.Errorf("invalid status type %q (valid: %s)", value, strings.Join(workflowStateTypes, ", "))
+	}
+
+	return value, nil
+}
+
+// findWorkflowStateByType returns the team's first state of stateType.
+func findWorkflowStateByType(states []WorkflowState, stateType string) *WorkflowState {
+	for i := range states {
+		if states[i].Type == stateType {
+			return &states[i]
 		}
 	}
-	if selectedTeam == nil {
-		fmt.Println("❌ Selected team not found")
-		os.Exit(1)
+
+	return nil
+}
+
+// statusFromType resolves a --status-type to the team's matching state id.
+func statusFromType(states []WorkflowState, stateType, teamName string) (string, error) {
+	state := findWorkflowStateByType(states, stateType)
+	if state == nil {
+		return "", fmt.Errorf("%s has no %s state", teamName, stateType)
 	}
 
-	// Fetch team labels, users, and workflow states
-	var labels []Label
-	var users []User
-	var workflowStates []WorkflowState
+	return state.ID, nil
+}
 
-	labels, err = loadTeamLabels(apiKey, selectedTeamId)
-	if err != nil {
-		fmt.Printf("❌ Error fetching labels: %v\n", err)
+func runStatus(ctx context.Context, apiKey, identifier, stateName string) {
+	if err := requireAPIKey(apiKey, "updating issue status"); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
 		os.Exit(1)
 	}
 
-	users, err = loadTeamUsers(apiKey, selectedTeamId)
+	state, err := moveIssue(ctx, apiKey, identifier, stateName)
 	if err != nil {
-		fmt.Printf("❌ Error fetching users: %v\n", err)
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" %s: %v\n", identifier, err)
+		os.Exit(1)
+	}
+
+	if quietOutput {
+		return
+	}
+	fmt.Printf(successSymbol+" Moved %s to %s\n", strings.ToUpper(identifier), state.Name)
+}
+
+// runBulkStatus moves every issue listed in input (one identifier per line)
+// to stateName, reporting each one and carrying on past failures.
+func runBulkStatus(ctx context.Context, apiKey string, input io.Reader, stateName string) {
+	ctx = withAPIKeyRotation(ctx)
+	if err := requireAPIKey(apiKey, "updating issue status"); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" %v\n", err)
 		os.Exit(1)
 	}
 
-	workflowStates, err = loadWorkflowStates(apiKey, selectedTeamId)
+	data, err := io.ReadAll(input)
 	if err != nil {
-		fmt.Printf("❌ Error fetching workflow states: %v\n", err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Error reading identifiers: %v\n", err)
 		os.Exit(1)
 	}
+	identifiers := parseIdentifierList(string(data))
+	if len(identifiers) == 0 {
+		infof("No issue identifiers on stdin\n")
+		return
+	}
 
-	// Create options
-	estimateOptions := getEstimateOptions(1) // Default to story points
+	failures := 0
+	for _, identifier := range identifiers {
+		state, err := moveIssue(ctx, apiKey, identifier, stateName)
+		if err != nil {
+			exitOnCancel(err)
+			failures++
+			fmt.Fprintf(os.Stderr, errorSymbol+" %s: %v\n", identifier, err)
+			continue
+		}
+		if !quietOutput {
+			fmt.Printf(successSymbol+" Moved %s to %s\n", identifier, state.Name)
+		}
+	}
 
-	labelOptions, labelMap := labelOptions(labels)
+	if !quietOutput {
+		fmt.Printf("\nMoved %d of %d issues (%d failed)\n", len(identifiers)-failures, len(identifiers), failures)
+	}
+	if failures > 0 {
+		os.Exit(1)
+	}
+}
 
-	userOptions := make([]huh.Option[string], len(users)+1) // +1 for "No assignee"
-	userOptions[0] = huh.Option[string]{Key: "No assignee", Value: ""}
-	for i, user := range users {
-		userOptions[i+1] = huh.Option[string]{Key: user.Name, Value: user.ID}
+// parseIdentifierList splits newline-separated identifiers, upper-casing them
+// and dropping blank lines and repeats.
+func parseIdentifierList(text string) []string {
+	var identifiers []string
+	for _, line := range strings.Split(text, "\n") {
+		identifier := strings.ToUpper(strings.TrimSpace(line))
+		if identifier != "" && !slices.Contains(identifiers, identifier) {
+			identifiers = append(identifiers, identifier)
+		}
 	}
 
-	statusOptions := make([]huh.Option[string], len(workflowStates))
-	for i, state := range workflowStates {
-		statusOptions[i] = huh.Option[string]{Key: state.Name, Value: state.ID}
+	return identifiers
+}
+
+// moveIssue moves the issue to the workflow state named stateName in its team.
+func moveIssue(ctx context.Context, apiKey, identifier, stateName string) (WorkflowState, error) {
+	if !issueIdentifierPattern.MatchString(identifier) {
+		return WorkflowState{}, fmt.Errorf("not an issue identifier")
 	}
+	identifier = strings.ToUpper(identifier)
 
-	// Set default values from cache
-	ticket.TeamId = selectedTeamId
-	ticket.Estimate = selections.Estimate
-	ticket.Labels = selections.Labels
-	ticket.AssigneeId = selections.AssigneeId
-	ticket.StatusId = selections.StatusId
+	ref, err := fetchIssueRef(ctx, apiKey, identifier)
+	if err != nil {
+		return WorkflowState{}, fmt.Errorf("finding issue: %w", err)
+	}
 
-	// Create the form
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("Ticket Title").
-				Description("A brief summary of the issue or feature").
-				Value(&ticket.Title).
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("title cannot be empty")
-					}
-					return nil
-				}),
+	states, err := loadWorkflowStates(ctx, apiKey, ref.TeamID)
+	if err != nil {
+		return WorkflowState{}, fmt.Errorf("fetching workflow states: %w", err)
+	}
+	state := findWorkflowState(states, stateName)
+	if state == nil {
+		// The state may have been added since the cache was written
+		if states, err = fetchWorkflowStates(ctx, apiKey, ref.TeamID); err == nil {
+			saveToCache("states-"+ref.TeamID, states)
+			state = findWorkflowState(states, stateName)
+		}
+	}
+	if state == nil {
+		return WorkflowState{}, fmt.Errorf("status %q not found (valid: %s)", stateName, strings.Join(workflowStateNames(states), ", "))
+	}
 
-			huh.NewText().
-				Title("Description").
-				Description("Detailed description of the ticket").
-				Value(&ticket.Description).
-				Lines(5),
+	if err := updateIssueState(ctx, apiKey, ref.ID, state.ID); err != nil {
+		return WorkflowState{}, fmt.Errorf("updating status: %w", err)
+	}
 
-			huh.NewSelect[string]().
-				Title("Status").
-				Description("Select the status for this ticket").
-				Options(statusOptions...).
-				Value(&ticket.StatusId),
+	return *state, nil
+}
 
-			huh.NewSelect[string]().
-				Title("Estimate").
-				Description("Story point estimate").
-				Options(estimateOptions...).
-				Value(&ticket.Estimate),
+// parseStatusTo returns the state name given with --to (or --to=), or "" when
+// the status command wasn't called in bulk form.
+func parseStatusTo(args []string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--to="); ok {
+			return strings.TrimSpace(value)
+		}
+		if arg == "--to" && i+1 < len(args) {
+			return strings.TrimSpace(strings.Join(args[i+1:], " "))
+		}
+	}
 
-			huh.NewMultiSelect[string]().
-				Title("Labels").
-				Description("Select applicable labels (space to toggle, enter to confirm)").
-				Options(labelOptions...).
-				Value(&ticket.Labels).
-				Limit(4),
+	return ""
+}
 
-			huh.NewSelect[string]().
-				Title("Assignee").
-				Description("Select who should work on this ticket").
-				Options(userOptions...).
-				Value(&ticket.AssigneeId),
-		),
-	)
+// createBlocksRelation records that blockingId blocks blockedId.
+func createBlocksRelation(ctx context.Context, apiKey, blockingId, blockedId string) error {
+	mutation := `
+		mutation IssueRelationCreate($input: IssueRelationCreateInput!) {
+			issueRelationCreate(input: $input) {
+				success
+			}
+		}
+	`
 
-	// Run the form
-	err = form.Run()
-	if err != nil {
-		fmt.Println("Form cancelled or error:", err)
-		os.Exit(1)
+	_, err := makeLinearRequest(ctx, apiKey, mutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"issueId":        blockingId,
+			"relatedIssueId": blockedId,
+			"type":           "blocks",
+		},
+	})
+	return err
+}
+
+// relateIssuesFromOptions links the created issue to the --blocks and
+// --blocked-by issues; failures are reported but don't undo the created issue.
+func relateIssuesFromOptions(ctx context.Context, apiKey string, issue CreatedIssue, options CreateOptions) {
+	if len(options.Blocks) == 0 && len(options.BlockedBy) == 0 {
+		return
 	}
 
-	// Display the collected information
-	fmt.Println("\n" + "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("📝 Ticket Information")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("Title:       %s\n", ticket.Title)
-	fmt.Printf("Description: %s\n", ticket.Description)
+	if err := requireAPIKey(apiKey, "issue relations"); err != nil {
+		fmt.Fprintf(os.Stderr, errorSymbol+" Failed to add issue relations: %v\n", err)
+		return
+	}
 
-	// Show estimate with proper name
-	estimateText := "No estimate"
-	if ticket.Estimate != "" && ticket.Estimate != "0" {
-		for _, option := range estimateOptions {
-			if option.Value == ticket.Estimate {
-				estimateText = option.Key
-				break
+	ref, err := fetchIssueRef(ctx, apiKey, issue.Identifier)
+	if err != nil {
+		exitOnCancel(err)
+		fmt.Fprintf(os.Stderr, errorSymbol+" Failed to add issue relations: %v\n", err)
+		return
+	}
+
+	relate := func(identifier string, blocks bool) {
+		related, err := fetchIssueRef(ctx, apiKey, identifier)
+		if err == nil {
+			if blocks {
+				err = createBlocksRelation(ctx, apiKey, ref.ID, related.ID)
+			} else {
+				err = createBlocksRelation(ctx, apiKey, related.ID, ref.ID)
 			}
 		}
+		if err != nil {
+			exitOnCancel(err)
+			fmt.Fprintf(os.Stderr, errorSymbol+" Failed to link %s: %v\n", identifier, err)
+		}
+	}
+	for _, identifier := range options.Blocks {
+		relate(identifier, true)
+	}
+	for _, identifier := range options.BlockedBy {
+		relate(identifier, false)
+	}
+}
+
+func printTicketSummary(ticket LinearTicket, team Team, estimateType int, workflowStates []WorkflowState, users []User) {
+	estimateText := estimateLabel(estimateType, ticket.Estimate)
+	if hours := estimateHours(ticket.Estimate, loadConfig().HoursPerPoint); hours != "" {
+		estimateText += " (" + hours + ")"
 	}
-	fmt.Printf("Estimate:    %s\n", estimateText)
 
 	// Show status name
 	statusName := "Unknown"
@@ -2389,7 +8411,6 @@ func main() {
 			}
 		}
 	}
-	fmt.Printf("Status:      %s\n", statusName)
 
 	// Show assignee name
 	assigneeName := "No Assignee"
@@ -2401,88 +8422,204 @@ func main() {
 			}
 		}
 	}
-	fmt.Printf("Assignee:    %s\n", assigneeName)
 
-	// Show labels
+	rows := []summaryRow{
+		{"Team", team.Name},
+		{"Title", ticket.Title},
+		{"Description", ticket.Description},
+		{"Estimate", estimateText},
+		{"Priority", priorityLabel(ticket.Priority)},
+		{"Status", statusName},
+		{"Assignee", assigneeName},
+	}
+	if len(ticket.SubscriberIds) > 0 {
+		rows = append(rows, summaryRow{"Subscribers", strings.Join(userNames(users, ticket.SubscriberIds), ", ")})
+	}
+	labels := "None"
 	if len(ticket.Labels) > 0 {
-		fmt.Printf("Labels:      %s\n", strings.Join(ticket.Labels, ", "))
-	} else {
-		fmt.Printf("Labels:      None\n")
+		labels = strings.Join(ticket.Labels, ", ")
+	}
+	rows = append(rows, summaryRow{"Labels", labels})
+
+	// Piped output gets plain key: value lines instead of a box
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		fmt.Print(formatSummary(rows, 0))
+		return
+	}
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || width <= 0 || width > maxSummaryWidth {
+		width = maxSummaryWidth
+	}
+	fmt.Print("\n" + formatSummary(rows, width))
+}
+
+type summaryRow struct {
+	Label string
+	Value string
+}
+
+// maxSummaryWidth caps the summary box on wide terminals.
+const maxSummaryWidth = 80
+
+// formatSummary renders rows in a box width columns wide, collapsing each
+// value to one line and cutting it off with an ellipsis where it would wrap.
+// A width of 0 drops the box and prints every value in full.
+func formatSummary(rows []summaryRow, width int) string {
+	var b strings.Builder
+	if width <= 0 {
+		for _, row := range rows {
+			fmt.Fprintf(&b, "%s: %s\n", row.Label, row.Value)
+		}
+		return b.String()
+	}
+
+	rule := strings.Repeat(string([]rune(ruleLine)[0]), width)
+	b.WriteString(rule + "\n")
+	b.WriteString(truncateText(ticketSymbol+" Ticket Information", width) + "\n")
+	b.WriteString(rule + "\n")
+	for _, row := range rows {
+		label := fmt.Sprintf("%-13s", row.Label+":")
+		value := strings.Join(strings.Fields(row.Value), " ")
+		b.WriteString(truncateText(label+value, width) + "\n")
+	}
+	b.WriteString(rule + "\n")
+
+	return b.String()
+}
+
+// truncateText cuts text to width display columns, ending in an ellipsis
+// when anything was cut.
+func truncateText(text string, width int) string {
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+lipgloss.Width(ellipsisSymbol) > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + ellipsisSymbol
+}
+
+// renderMarkdown renders a description for the terminal with glamour so
+// formatting mistakes show up before a ticket is created. --plain uses
+// glamour's ASCII style and NO_COLOR its uncolored one; if rendering fails
+// the text is shown as is.
+func renderMarkdown(text string) string {
+	style := glamour.WithAutoStyle()
+	if plainOutput {
+		style = glamour.WithStandardStyle(styles.AsciiStyle)
+	} else if os.Getenv("NO_COLOR") != "" {
+		style = glamour.WithStandardStyle(styles.NoTTYStyle)
+	}
+	width := maxSummaryWidth
+	if termWidth, _, err := term.GetSize(os.Stdout.Fd()); err == nil && termWidth > 0 && termWidth < width {
+		width = termWidth
 	}
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	fmt.Println("\n🚀 Creating ticket in Linear...")
-	issue, err := createLinearTicket(apiKey, ticket, labelMap)
+	renderer, err := glamour.NewTermRenderer(style, glamour.WithWordWrap(width))
 	if err != nil {
-		fmt.Printf("❌ Error creating ticket: %v\n", err)
-		os.Exit(1)
+		return text
+	}
+	rendered, err := renderer.Render(text)
+	if err != nil {
+		return text
 	}
 
-	fmt.Printf("✅ Ticket created successfully! ID: %s\n", issue.Identifier)
+	return strings.Trim(rendered, "\n")
+}
 
-	// Save user selections to cache
-	selections = UserSelections{
-		TeamId:     ticket.TeamId,
-		AssigneeId: ticket.AssigneeId,
-		Labels:     ticket.Labels,
-		Estimate:   ticket.Estimate,
-		StatusId:   ticket.StatusId,
+// positionSortOrder returns a sortOrder that places a new issue above the
+// team's first issue or below its last in manual order. ok is false when the
+// team has no issues yet, leaving placement to Linear.
+func positionSortOrder(ctx context.Context, apiKey, teamId, position string) (float64, bool, error) {
+	order := "Ascending"
+	if position == "bottom" {
+		order = "Descending"
 	}
-	saveUserSelections(selections)
 
-	// Post-creation menu
-	var action string
-	postForm := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("What would you like to do?").
-				Options(
-					huh.Option[string]{Key: "Copy branch name", Value: "branch"},
-					huh.Option[string]{Key: "Open in Linear", Value: "open"},
-					huh.Option[string]{Key: "Exit", Value: "exit"},
-				).
-				Value(&action),
-		),
-	)
+	query := `
+		query EdgeSortOrder($teamId: ID!, $order: PaginationSortOrder!) {
+			issues(filter: { team: { id: { eq: $teamId } } }, first: 1, sort: [{ manual: { order: $order } }]) {
+				nodes {
+					sortOrder
+				}
+			}
+		}
+	`
 
-	if err := postForm.Run(); err != nil {
-		fmt.Println("Menu cancelled or error:", err)
-		return
+	result, err := makeLinearRequest(ctx, apiKey, query, map[string]interface{}{"teamId": teamId, "order": order})
+	if err != nil {
+		return 0, false, err
 	}
 
-	switch action {
-	case "branch":
-		branchName := fallbackBranchName(issue)
-		if err := clipboard.WriteAll(branchName); err != nil {
-			fmt.Printf("❌ Failed to copy to clipboard: %v\n", err)
+	data, _ := result["data"].(map[string]interface{})
+	issues, _ := data["issues"].(map[string]interface{})
+	nodes, _ := issues["nodes"].([]interface{})
+	if len(nodes) == 0 {
+		return 0, false, nil
+	}
+	node, _ := nodes[0].(map[string]interface{})
+	edge, ok := node["sortOrder"].(float64)
+	if !ok {
+		return 0, false, nil
+	}
+
+	if position == "bottom" {
+		return edge + positionSortOrderGap, true, nil
+	}
+	return edge - positionSortOrderGap, true, nil
+}
+
+// positionSortOrderGap is how far past the edge issue --position places a
+// new one, leaving room to drag others in between.
+const positionSortOrderGap = 1000
+
+// knownLabels splits names into those in labelMap and those it doesn't have,
+// such as saved defaults for a label that has since been deleted.
+func knownLabels(names []string, labelMap map[string]string) (known, dropped []string) {
+	for _, name := range names {
+		if _, ok := labelMap[name]; ok {
+			known = append(known, name)
 		} else {
-			fmt.Printf("📋 Copied '%s' to clipboard\n", branchName)
-		}
-	case "open":
-		// Get the full URL from the issue data
-		url := fmt.Sprintf("https://linear.app/issue/%s", issue.Identifier)
-		var cmd *exec.Cmd
-		switch runtime.GOOS {
-		case "windows":
-			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-		case "darwin":
-			cmd = exec.Command("open", url)
-		case "linux":
-			cmd = exec.Command("xdg-open", url)
-		}
-		if cmd != nil {
-			if err := cmd.Run(); err != nil {
-				fmt.Printf("❌ Failed to open URL: %v\n", err)
-			}
+			dropped = append(dropped, name)
 		}
-	case "exit":
-		// Do nothing, just exit
 	}
+
+	return known, dropped
 }
 
-func createLinearTicket(apiKey string, ticket LinearTicket, labelMap map[string]string) (CreatedIssue, error) {
+func createLinearTicket(ctx context.Context, apiKey string, ticket LinearTicket, labelMap map[string]string) (CreatedIssue, error) {
+	if len(ticket.Fields) > 0 {
+		if err := requireAPIKey(apiKey, "--field"); err != nil {
+			return CreatedIssue{}, err
+		}
+	}
+	if ticket.Position != "" {
+		if err := requireAPIKey(apiKey, "--position"); err != nil {
+			return CreatedIssue{}, err
+		}
+	}
+	if len(ticket.Images) > 0 {
+		if err := requireAPIKey(apiKey, "--attach-image"); err != nil {
+			return CreatedIssue{}, err
+		}
+	}
+
+	labels, dropped := knownLabels(ticket.Labels, labelMap)
+	if len(dropped) > 0 {
+		if strictLabels {
+			return CreatedIssue{}, fmt.Errorf("labels not found in this team: %s", strings.Join(dropped, ", "))
+		}
+		fmt.Fprintf(os.Stderr, infoSymbol+" Skipping labels not found in this team: %s\n", strings.Join(dropped, ", "))
+	}
+	ticket.Labels = labels
 	if authHeader, ok := splitMCPAuthHeader(apiKey); ok {
-		return createLinearTicketWithMCP(authHeader, ticket)
+		if len(ticket.SubscriberIds) > 0 {
+			fmt.Fprintln(os.Stderr, infoSymbol+" Skipping subscribers: they require a Linear API key (LINEAR_API_KEY)")
+		}
+		return createLinearTicketWithMCP(ctx, authHeader, ticket)
 	}
 
 	// GraphQL mutation to create an issue
@@ -2507,6 +8644,25 @@ func createLinearTicket(apiKey string, ticket LinearTicket, labelMap map[string]
 		"title":       ticket.Title,
 		"description": ticket.Description,
 	}
+	if ticket.ID != "" {
+		input["id"] = ticket.ID
+	}
+	if ticket.ProjectId != "" {
+		input["projectId"] = ticket.ProjectId
+	}
+	if ticket.ParentId != "" {
+		input["parentId"] = ticket.ParentId
+	}
+	if ticket.ProjectMilestoneId != "" {
+		input["projectMilestoneId"] = ticket.ProjectMilestoneId
+	}
+	if ticket.TemplateId != "" {
+		input["templateId"] = ticket.TemplateId
+		// An empty description would replace the template's
+		if ticket.Description == "" {
+			delete(input, "description")
+		}
+	}
 
 	// Add estimate if provided
 	if ticket.Estimate != "" && ticket.Estimate != "0" {
@@ -2519,9 +8675,7 @@ func createLinearTicket(apiKey string, ticket LinearTicket, labelMap map[string]
 	if len(ticket.Labels) > 0 {
 		var labelIds []string
 		for _, labelName := range ticket.Labels {
-			if labelId, exists := labelMap[labelName]; exists {
-				labelIds = append(labelIds, labelId)
-			}
+			labelIds = append(labelIds, labelMap[labelName])
 		}
 		if len(labelIds) > 0 {
 			input["labelIds"] = labelIds
@@ -2538,42 +8692,53 @@ func createLinearTicket(apiKey string, ticket LinearTicket, labelMap map[string]
 		input["stateId"] = ticket.StatusId
 	}
 
-	payload := map[string]interface{}{
-		"query": mutation,
-		"variables": map[string]interface{}{
-			"input": input,
-		},
+	// Add subscribers if provided
+	if len(ticket.SubscriberIds) > 0 {
+		input["subscriberIds"] = ticket.SubscriberIds
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return CreatedIssue{}, err
+	// Add priority if provided
+	if ticket.Priority != "" && ticket.Priority != "0" {
+		if priority, err := strconv.Atoi(ticket.Priority); err == nil {
+			input["priority"] = priority
+		}
 	}
 
-	// Make the API request
-	req, err := http.NewRequest("POST", "https://api.linear.app/graphql", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return CreatedIssue{}, err
+	if ticket.Position != "" {
+		sortOrder, ok, err := positionSortOrder(ctx, apiKey, ticket.TeamId, ticket.Position)
+		if err != nil {
+			return CreatedIssue{}, err
+		}
+		if ok {
+			input["sortOrder"] = sortOrder
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return CreatedIssue{}, err
+	// Images are uploaded once every other check has passed, so a rejected
+	// ticket doesn't leave files published to Linear's storage
+	if len(ticket.Images) > 0 {
+		description, err := uploadDescriptionImages(ctx, apiKey, ticket.Description, ticket.Images)
+		if err != nil {
+			return CreatedIssue{}, err
+		}
+		input["description"] = description
 	}
-	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return CreatedIssue{}, err
+	// --field values go in last and win over the fields above
+	for name, value := range ticket.Fields {
+		input[name] = value
 	}
 
-	// Check for errors
-	if errors, ok := result["errors"].([]interface{}); ok && len(errors) > 0 {
-		return CreatedIssue{}, fmt.Errorf("Linear API error: %v", errors)
+	result, err := makeLinearRequest(ctx, apiKey, mutation, map[string]interface{}{"input": input})
+	if err != nil {
+		// A retry of a create that reached Linear fails because the id is
+		// taken; return the issue made the first time
+		if ticket.ID != "" && !errors.Is(err, context.Canceled) {
+			if issue, fetchErr := fetchCreatedIssue(ctx, apiKey, ticket.ID); fetchErr == nil {
+				return issue, nil
+			}
+		}
+		return CreatedIssue{}, err
 	}
 
 	// Extract issue ID